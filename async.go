@@ -0,0 +1,205 @@
+package gologger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what happens to a record when a sink's queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the logging call wait until the queue has room
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the record that does not fit
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest queued record to make room
+	OverflowDropOldest
+	// OverflowDropBelowLevel discards records below AsyncConfig.DropBelow and blocks for all others
+	OverflowDropBelowLevel
+)
+
+// AsyncConfig configures asynchronous dispatch, where every callback and sink gets its own bounded queue
+type AsyncConfig struct {
+	QueueSize int            // Capacity of each sink's queue, defaults to 1024
+	Workers   int            // Number of goroutines draining each sink's queue, defaults to 1 to keep records in order
+	Overflow  OverflowPolicy // What to do when a queue is full
+	DropBelow slog.Level     // Level below which records are dropped with OverflowDropBelowLevel
+}
+
+// asyncQueue is the bounded queue of a single callback, drained by its own workers
+type asyncQueue struct {
+	cfg     AsyncConfig
+	items   chan Record
+	pending atomic.Int64 // records enqueued but not yet processed
+	dropped *atomic.Uint64
+	mu      sync.RWMutex // guards closed against concurrent enqueues
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// EnableAsync switches the default logger to asynchronous dispatch
+func EnableAsync(cfg AsyncConfig) error { return defaultLogger.EnableAsync(cfg) }
+
+// EnableAsync switches the logger to asynchronous dispatch, so slow sinks no longer block the logging call.
+// Use Flush to wait until all queued records are processed.
+func (l *Logger) EnableAsync(cfg AsyncConfig) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.async != nil {
+		return fmt.Errorf("async dispatch is already enabled")
+	}
+	l.enableAsync(cfg)
+	return nil
+}
+
+// WithAsync enables asynchronous dispatch on the logger, see EnableAsync
+func WithAsync(cfg AsyncConfig) Option {
+	return func(l *Logger) { l.enableAsync(cfg) }
+}
+
+// enableAsync stores the config and starts queues for the existing callbacks, the caller must hold l.mu
+func (l *Logger) enableAsync(cfg AsyncConfig) {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	l.async = &cfg
+
+	callbacks := make([]callbackEntry, len(l.callbacks))
+	for i, entry := range l.callbacks {
+		entry.queue = l.newQueue(entry)
+		callbacks[i] = entry
+	}
+	l.callbacks = callbacks
+}
+
+// Dropped returns the number of records the default logger discarded because a queue was full
+func Dropped() uint64 { return defaultLogger.Dropped() }
+
+// Dropped returns the number of records the logger discarded because a queue was full
+func (l *Logger) Dropped() uint64 { return l.dropped.Load() }
+
+// newQueue creates a queue for the callback entry and starts its workers, the caller must hold l.mu
+func (l *Logger) newQueue(entry callbackEntry) *asyncQueue {
+	q := &asyncQueue{
+		cfg:     *l.async,
+		items:   make(chan Record, l.async.QueueSize),
+		dropped: &l.dropped,
+	}
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for item := range q.items {
+				l.invokeCallback(entry, item)
+				q.pending.Add(-1)
+			}
+		}()
+	}
+	return q
+}
+
+// enqueue adds a record to the queue, applying the overflow policy if it is full
+func (q *asyncQueue) enqueue(item Record) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		// the callback was removed while the record was dispatched
+		return
+	}
+
+	q.pending.Add(1)
+	select {
+	case q.items <- item:
+		return
+	default:
+	}
+
+	switch {
+	case q.cfg.Overflow == OverflowDropNewest,
+		q.cfg.Overflow == OverflowDropBelowLevel && item.Level < q.cfg.DropBelow:
+		q.drop()
+	case q.cfg.Overflow == OverflowDropOldest:
+		for {
+			select {
+			case q.items <- item:
+				return
+			default:
+			}
+			select {
+			case <-q.items:
+				q.drop()
+			default:
+			}
+		}
+	default:
+		q.items <- item
+	}
+}
+
+// drop accounts for a discarded record
+func (q *asyncQueue) drop() {
+	q.pending.Add(-1)
+	q.dropped.Add(1)
+}
+
+// close stops accepting records and waits until the workers processed everything already queued.
+// It returns ctx.Err() if ctx ends first, the workers then finish the queue in the background.
+func (q *asyncQueue) close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.items)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	if q.pending.Load() == 0 {
+		// idle workers exit right away, even if ctx already ended
+		<-done
+		return nil
+	}
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drainQueues waits until all queued records are processed, or returns ctx.Err() if ctx ends first
+func (l *Logger) drainQueues(ctx context.Context) error {
+	l.mu.RLock()
+	var queues []*asyncQueue
+	for _, entry := range l.callbacks {
+		if entry.queue != nil {
+			queues = append(queues, entry.queue)
+		}
+	}
+	l.mu.RUnlock()
+
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	for _, q := range queues {
+		for q.pending.Load() > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package gologger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAsyncDispatch(t *testing.T) {
+	t.Run("flush drains queues", func(t *testing.T) {
+		l := NewLogger(WithAsync(AsyncConfig{QueueSize: 100}))
+
+		var mu sync.Mutex
+		var received []string
+		l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			received = append(received, msg)
+			mu.Unlock()
+		})
+
+		for i := 0; i < 10; i++ {
+			l.Info("test")
+		}
+		if err := l.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(received) != 10 {
+			t.Errorf("expected 10 records after flush, got %d", len(received))
+		}
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		l := NewLogger(WithAsync(AsyncConfig{QueueSize: 1, Overflow: OverflowDropNewest}))
+
+		release := make(chan struct{})
+		remove := l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { <-release })
+
+		// the first record blocks the worker, the second fills the queue
+		l.Info("first")
+		time.Sleep(10 * time.Millisecond)
+		l.Info("second")
+		l.Info("dropped")
+
+		if l.Dropped() != 1 {
+			t.Errorf("expected 1 dropped record, got %d", l.Dropped())
+		}
+		close(release)
+		remove()
+	})
+
+	t.Run("drop below level", func(t *testing.T) {
+		l := NewLogger(WithAsync(AsyncConfig{QueueSize: 1, Overflow: OverflowDropBelowLevel, DropBelow: slog.LevelWarn}))
+
+		release := make(chan struct{})
+		var errorCount int
+		l.RegisterRecordCallback(slog.LevelInfo, func(r Record) {
+			<-release
+			if r.Level == slog.LevelError {
+				errorCount++
+			}
+		})
+
+		l.Info("first")
+		time.Sleep(10 * time.Millisecond)
+		l.Info("second")
+		l.Info("dropped")
+
+		done := make(chan struct{})
+		go func() {
+			l.Error("blocks until there is room")
+			close(done)
+		}()
+		close(release)
+		<-done
+
+		if err := l.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if l.Dropped() != 1 || errorCount != 1 {
+			t.Errorf("expected 1 dropped record and 1 error, got %d and %d", l.Dropped(), errorCount)
+		}
+	})
+
+	t.Run("flush respects context", func(t *testing.T) {
+		l := NewLogger(WithAsync(AsyncConfig{}))
+
+		release := make(chan struct{})
+		remove := l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { <-release })
+		l.Info("test")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := l.Flush(ctx); err != context.DeadlineExceeded {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+		close(release)
+		remove()
+	})
+}
@@ -0,0 +1,350 @@
+package gologger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Config describes a logger declaratively, see Configure. It can be loaded from JSON, YAML and GOLOGGER_*
+// environment variables, which all use the JSON field names.
+type Config struct {
+	Level     string            `json:"level"`      // Level of the logger, defaults to info
+	Modules   map[string]string `json:"modules"`    // Levels of named loggers by module, see SetModuleLevel
+	Labels    map[string]string `json:"labels"`     // Labels attached to every record
+	AddSource bool              `json:"add_source"` // Whether records carry their call site, see SetAddSource
+	Stringers StringerConfig    `json:"stringers"`  // Formatting of common value types
+	Sinks     []SinkConfig      `json:"sinks"`      // Sinks to set up
+}
+
+// StringerConfig registers stringers for common value types
+type StringerConfig struct {
+	TimeFormat   string `json:"time_format"`   // Layout of time.Time values, either a layout or the name of a time constant like "RFC3339" or "DateOnly"
+	DurationUnit string `json:"duration_unit"` // Renders time.Duration values as a number of this unit, one of ns, us, ms, s, m or h
+}
+
+// SinkConfig describes a sink, the fields apply to the types noted next to them
+type SinkConfig struct {
+	Type         string            `json:"type"`          // One of console, file, loki, sqlite, mysql, postgres or mssql
+	MinLevel     string            `json:"min_level"`     // Minimum level of the sink, defaults to debug
+	Labels       map[string]string `json:"labels"`        // file, loki and databases: labels added to every record
+	Path         string            `json:"path"`          // file: path of the log file
+	JSON         bool              `json:"json"`          // file: whether to write JSON lines
+	TimeFormat   string            `json:"time_format"`   // file and databases: layout or name of a time constant, defaults to RFC3339
+	URL          string            `json:"url"`           // loki: URL of the Loki server
+	BatchWait    string            `json:"batch_wait"`    // loki: maximum time logs are buffered, e.g. "5s"
+	Tenant       string            `json:"tenant"`        // loki: tenant ID
+	Table        string            `json:"table"`         // databases: name of the log table
+	Driver       string            `json:"driver"`        // databases: registered database/sql driver, defaults to the dialect's common driver name
+	DSN          string            `json:"dsn"`           // databases: data source name passed to sql.Open
+	SourceColumn bool              `json:"source_column"` // databases: whether to add a source column, see DbConfig
+	DB           *sql.DB           `json:"-"`             // databases: existing connection used instead of opening DSN
+}
+
+// ConfigError is an invalid field of a Config or of the document or environment it is loaded from
+type ConfigError struct {
+	Field string // path of the field like "sinks[1].min_level", or the environment variable
+	Err   error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid config field %s: %v", e.Field, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// configFieldError returns a ConfigError for the field
+func configFieldError(field string, format string, args ...any) error {
+	return &ConfigError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// defaultDrivers are the driver names used for database sinks without a driver
+var defaultDrivers = map[string]string{
+	"sqlite":   "sqlite3",
+	"mysql":    "mysql",
+	"postgres": "postgres",
+	"mssql":    "sqlserver",
+}
+
+// timeLayouts are the time constants that can be referred to by name
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// durationUnits are the units of StringerConfig.DurationUnit
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// configuredSink sets up a sink on a logger and returns a function that removes it again
+type configuredSink func(l *Logger) (func(), error)
+
+// compiledConfig is a validated Config
+type compiledConfig struct {
+	level     slog.Level
+	modules   map[string]slog.Level
+	stringers []func(l *Logger)
+	sinks     []configuredSink
+}
+
+// Configure configures the default logger, see Logger.Configure
+func Configure(cfg Config) error { return defaultLogger.Configure(cfg) }
+
+// Configure validates cfg and applies it to the logger: it sets the level, replaces the module levels and labels,
+// registers the stringers and sets up the sinks in order. Sinks that were set up before are kept.
+// Nothing is changed if cfg is invalid or a sink cannot be set up, validation errors are returned as ConfigError.
+func (l *Logger) Configure(cfg Config) error {
+	compiled, err := cfg.compile()
+	if err != nil {
+		return err
+	}
+
+	removes := make([]func(), 0, len(compiled.sinks))
+	for i, setup := range compiled.sinks {
+		remove, err := setup(l)
+		if err != nil {
+			for _, remove := range removes {
+				remove()
+			}
+			return fmt.Errorf("failed to set up sinks[%d]: %w", i, err)
+		}
+		removes = append(removes, remove)
+	}
+
+	for _, register := range compiled.stringers {
+		register(l)
+	}
+	// copy the labels, records share them with the sinks after the config may have been modified
+	var labels map[string]string
+	if cfg.Labels != nil {
+		labels = make(map[string]string, len(cfg.Labels))
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+	}
+	l.mu.Lock()
+	l.level = compiled.level
+	l.modules = compiled.modules
+	l.labels = labels
+	l.addSource = cfg.AddSource
+	l.mu.Unlock()
+	return nil
+}
+
+// Validate reports the first invalid field of the config as ConfigError
+func (c Config) Validate() error {
+	_, err := c.compile()
+	return err
+}
+
+// compile validates the config and prepares its application
+func (c Config) compile() (*compiledConfig, error) {
+	compiled := &compiledConfig{level: slog.LevelInfo, modules: make(map[string]slog.Level, len(c.Modules))}
+	if c.Level != "" {
+		level, err := ParseLevel(c.Level)
+		if err != nil {
+			return nil, &ConfigError{Field: "level", Err: err}
+		}
+		compiled.level = level
+	}
+
+	modules := make([]string, 0, len(c.Modules))
+	for module := range c.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules) // report the same field for the same config
+	for _, module := range modules {
+		if module == "" {
+			return nil, configFieldError("modules", "empty module name")
+		}
+		level, err := ParseLevel(c.Modules[module])
+		if err != nil {
+			return nil, &ConfigError{Field: "modules." + module, Err: err}
+		}
+		compiled.modules[module] = level
+	}
+
+	if c.Stringers.TimeFormat != "" {
+		layout, err := timeLayout("stringers.time_format", c.Stringers.TimeFormat)
+		if err != nil {
+			return nil, err
+		}
+		compiled.stringers = append(compiled.stringers, func(l *Logger) {
+			RegisterStringerFor(l, func(t time.Time) string { return t.Format(layout) })
+		})
+	}
+	if c.Stringers.DurationUnit != "" {
+		unit, ok := durationUnits[c.Stringers.DurationUnit]
+		if !ok {
+			return nil, configFieldError("stringers.duration_unit", "unknown unit %q, expected one of ns, us, ms, s, m or h", c.Stringers.DurationUnit)
+		}
+		compiled.stringers = append(compiled.stringers, func(l *Logger) {
+			RegisterStringerFor(l, func(d time.Duration) string {
+				return strconv.FormatFloat(float64(d)/float64(unit), 'f', -1, 64)
+			})
+		})
+	}
+
+	for i, sink := range c.Sinks {
+		setup, err := sink.compile(fmt.Sprintf("sinks[%d]", i))
+		if err != nil {
+			return nil, err
+		}
+		compiled.sinks = append(compiled.sinks, setup)
+	}
+	return compiled, nil
+}
+
+// compile validates the sink config, field names in errors are prefixed with path
+func (s SinkConfig) compile(path string) (configuredSink, error) {
+	minLevel := slog.LevelDebug
+	if s.MinLevel != "" {
+		level, err := ParseLevel(s.MinLevel)
+		if err != nil {
+			return nil, &ConfigError{Field: path + ".min_level", Err: err}
+		}
+		minLevel = level
+	}
+	timeFormat := time.RFC3339
+	if s.TimeFormat != "" {
+		layout, err := timeLayout(path+".time_format", s.TimeFormat)
+		if err != nil {
+			return nil, err
+		}
+		timeFormat = layout
+	}
+
+	switch s.Type {
+	case "console":
+		return func(l *Logger) (func(), error) {
+			sink := l.useConsole(minLevel)
+			return func() { sink.Close(context.Background()) }, nil
+		}, nil
+
+	case "file":
+		if s.Path == "" {
+			return nil, configFieldError(path+".path", "required for file sinks")
+		}
+		cfg := FileConfig{Path: s.Path, TimeFormat: timeFormat, FormatJson: s.JSON, LabelsMap: s.Labels, MinLevel: &minLevel}
+		return func(l *Logger) (func(), error) {
+			sink, err := l.UseFile(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return func() { sink.Close(context.Background()) }, nil
+		}, nil
+
+	case "loki":
+		if s.URL == "" {
+			return nil, configFieldError(path+".url", "required for loki sinks")
+		}
+		cfg := LokiConfig{URL: s.URL, Labels: s.Labels, Tenant: s.Tenant, MinLevel: &minLevel}
+		if s.BatchWait != "" {
+			wait, err := time.ParseDuration(s.BatchWait)
+			if err != nil {
+				return nil, &ConfigError{Field: path + ".batch_wait", Err: err}
+			}
+			if wait <= 0 {
+				return nil, configFieldError(path+".batch_wait", "%s is not positive", s.BatchWait)
+			}
+			cfg.BatchWait = wait
+		}
+		return func(l *Logger) (func(), error) {
+			sink, err := l.UseLoki(cfg)
+			if err != nil {
+				return nil, err
+			}
+			return func() { sink.Close(context.Background()) }, nil
+		}, nil
+
+	case "sqlite", "mysql", "postgres", "mssql":
+		if s.Table == "" {
+			return nil, configFieldError(path+".table", "required for database sinks")
+		}
+		driver := s.Driver
+		if driver == "" {
+			driver = defaultDrivers[s.Type]
+		}
+		if s.DB == nil {
+			if s.DSN == "" {
+				return nil, configFieldError(path+".dsn", "required for database sinks without DB")
+			}
+			if !driverRegistered(driver) {
+				return nil, configFieldError(path+".driver", "database driver %q is not registered, import it or set driver", driver)
+			}
+		}
+		dialect := s.Type
+		cfg := DbConfig{DB: s.DB, TableName: s.Table, TimeFormat: timeFormat, LabelsMap: s.Labels, MinLevel: &minLevel, SourceColumn: s.SourceColumn}
+		dsn := s.DSN
+		return func(l *Logger) (func(), error) {
+			if cfg.DB == nil {
+				db, err := sql.Open(driver, dsn)
+				if err != nil {
+					return nil, fmt.Errorf("failed to open database: %w", err)
+				}
+				cfg.DB = db
+			}
+			sink, err := l.setupDbLogger(cfg, dialect)
+			if err != nil {
+				if s.DB == nil {
+					cfg.DB.Close()
+				}
+				return nil, err
+			}
+			sink.ownsDB = s.DB == nil
+			return func() { sink.Close(context.Background()) }, nil
+		}, nil
+
+	case "":
+		return nil, configFieldError(path+".type", "required")
+	default:
+		return nil, configFieldError(path+".type", "unknown sink type %q, expected one of console, file, loki, sqlite, mysql, postgres or mssql", s.Type)
+	}
+}
+
+// timeLayout returns the layout of a time constant or validates a custom layout
+func timeLayout(field, format string) (string, error) {
+	if layout, ok := timeLayouts[format]; ok {
+		return layout, nil
+	}
+	// a layout without any element formats to itself
+	if time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC).Format(format) == format {
+		return "", configFieldError(field, "%q is neither a time layout nor the name of a time constant", format)
+	}
+	return format, nil
+}
+
+// driverRegistered reports whether a database/sql driver with the name is registered
+func driverRegistered(name string) bool {
+	for _, driver := range sql.Drivers() {
+		if driver == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package gologger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfigure(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+
+	yamlDoc := `
+# logging of the api
+level: warn
+modules:
+  db: debug
+labels: {}
+stringers:
+  time_format: DateOnly
+  duration_unit: ms
+sinks:
+  - type: file
+    path: ` + logPath + `
+    min_level: "info" # quoted scalars work too
+    labels:
+      app: api
+  - type: loki
+    url: http://localhost:3100
+    batch_wait: 5s
+`
+	jsonDoc := `{
+		"level": "warn",
+		"modules": {"db": "debug"},
+		"labels": {},
+		"stringers": {"time_format": "DateOnly", "duration_unit": "ms"},
+		"sinks": [
+			{"type": "file", "path": ` + strconv.Quote(logPath) + `, "min_level": "info", "labels": {"app": "api"}},
+			{"type": "loki", "url": "http://localhost:3100", "batch_wait": "5s"}
+		]
+	}`
+
+	fromYAML, err := ParseYAMLConfig([]byte(yamlDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := ParseJSONConfig([]byte(jsonDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("expected equal configs\nyaml: %+v\njson: %+v", fromYAML, fromJSON)
+	}
+
+	// flow collections, anchors, multi-line scalars and YAML 1.1 booleans
+	flowDoc := `
+modules: {db: debug}
+add_source: yes
+labels: &labels {app: api}
+sinks:
+  - {type: console, labels: *labels}
+  - type: file
+    path: >-
+      ` + logPath + `
+    labels:
+      <<: *labels
+      env: prod
+`
+	fromFlow, err := ParseYAMLConfig([]byte(flowDoc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Config{
+		Modules:   map[string]string{"db": "debug"},
+		AddSource: true,
+		Labels:    map[string]string{"app": "api"},
+		Sinks: []SinkConfig{
+			{Type: "console", Labels: map[string]string{"app": "api"}},
+			{Type: "file", Path: logPath, Labels: map[string]string{"app": "api", "env": "prod"}},
+		},
+	}
+	if !reflect.DeepEqual(fromFlow, expected) {
+		t.Errorf("expected %+v, got %+v", expected, fromFlow)
+	}
+
+	// environment variables override the document
+	configPath := filepath.Join(dir, "logging.yml")
+	if err := os.WriteFile(configPath, []byte(yamlDoc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOLOGGER_LEVEL", "error")
+	t.Setenv("GOLOGGER_SINKS_0_JSON", "true")
+	t.Setenv("GOLOGGER_SINKS_1_MIN_LEVEL", "error")
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != "error" || !cfg.Sinks[0].JSON || cfg.Sinks[0].Path != logPath || cfg.Sinks[1].MinLevel != "error" || cfg.Sinks[1].URL == "" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	// only configure the file sink, Loki is not reachable
+	cfg.Sinks = cfg.Sinks[:1]
+	l := NewLogger()
+	if err := l.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	state := l.LevelState()
+	if state.Level != "error" || state.Modules["db"] != "debug" || len(state.Sinks) != 1 || state.Sinks[0].MinLevel != "info" {
+		t.Errorf("unexpected state %+v", state)
+	}
+
+	l.Named("db").Info("query", "at", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), "took", 1500*time.Microsecond)
+	l.Warn("not logged")
+	if err := l.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := string(data)
+	if strings.Count(line, "\n") != 1 || !strings.Contains(line, `"at":"2024-03-01"`) || !strings.Contains(line, `"took":"1.5"`) || !strings.Contains(line, `"app":"api"`) {
+		t.Errorf("unexpected log file %s", line)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		field string
+	}{
+		{"level", Config{Level: "loud"}, "level"},
+		{"module level", Config{Modules: map[string]string{"db": "debug", "http": "loud"}}, "modules.http"},
+		{"time format", Config{Stringers: StringerConfig{TimeFormat: "yyyy-mm-dd"}}, "stringers.time_format"},
+		{"duration unit", Config{Stringers: StringerConfig{DurationUnit: "days"}}, "stringers.duration_unit"},
+		{"sink type", Config{Sinks: []SinkConfig{{Type: "console"}, {Type: "kafka"}}}, "sinks[1].type"},
+		{"missing type", Config{Sinks: []SinkConfig{{}}}, "sinks[0].type"},
+		{"sink level", Config{Sinks: []SinkConfig{{Type: "console", MinLevel: "loud"}}}, "sinks[0].min_level"},
+		{"file path", Config{Sinks: []SinkConfig{{Type: "file"}}}, "sinks[0].path"},
+		{"loki url", Config{Sinks: []SinkConfig{{Type: "loki"}}}, "sinks[0].url"},
+		{"batch wait", Config{Sinks: []SinkConfig{{Type: "loki", URL: "http://loki", BatchWait: "soon"}}}, "sinks[0].batch_wait"},
+		{"table", Config{Sinks: []SinkConfig{{Type: "sqlite", DSN: "logs.db"}}}, "sinks[0].table"},
+		{"dsn", Config{Sinks: []SinkConfig{{Type: "sqlite", Table: "logs"}}}, "sinks[0].dsn"},
+		{"driver", Config{Sinks: []SinkConfig{{Type: "postgres", Table: "logs", DSN: "postgres://localhost"}}}, "sinks[0].driver"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfgErr *ConfigError
+			if err := NewLogger().Configure(tt.cfg); !errors.As(err, &cfgErr) || cfgErr.Field != tt.field {
+				t.Errorf("expected error for field %s, got %v", tt.field, err)
+			}
+		})
+	}
+
+	t.Run("documents", func(t *testing.T) {
+		var cfgErr *ConfigError
+		if _, err := ParseJSONConfig([]byte(`{"sinks": [{"type": "file", "pth": "app.log"}]}`)); !errors.As(err, &cfgErr) || cfgErr.Field != "sinks[0].pth" {
+			t.Errorf("expected unknown field error, got %v", err)
+		}
+		if _, err := ParseYAMLConfig([]byte("sinks:\n  - type: file\n    json: maybe\n")); !errors.As(err, &cfgErr) || cfgErr.Field != "sinks[0].json" {
+			t.Errorf("expected invalid boolean error, got %v", err)
+		}
+		if _, err := ParseYAMLConfig([]byte("level: info\n  debug: true\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected syntax error naming the line, got %v", err)
+		}
+
+		t.Setenv("GOLOGGER_SINKS_0_PATHS", "app.log")
+		if _, err := LoadConfig(""); !errors.As(err, &cfgErr) || cfgErr.Field != "GOLOGGER_SINKS_0_PATHS" {
+			t.Errorf("expected unknown variable error, got %v", err)
+		}
+	})
+
+	t.Run("env indices", func(t *testing.T) {
+		var cfgErr *ConfigError
+		var cfg Config
+		if err := cfg.applyEnv([]string{"GOLOGGER_SINKS_1_TYPE=console"}); !errors.As(err, &cfgErr) || cfgErr.Field != "GOLOGGER_SINKS_1_TYPE" || !strings.Contains(err.Error(), "sinks[0] is not set") {
+			t.Errorf("expected sparse index error, got %v", err)
+		}
+
+		// indices are applied in numeric order
+		var environ []string
+		for i := 10; i >= 0; i-- {
+			environ = append(environ, "GOLOGGER_SINKS_"+strconv.Itoa(i)+"_TYPE=console")
+		}
+		cfg = Config{}
+		if err := cfg.applyEnv(environ); err != nil || len(cfg.Sinks) != 11 {
+			t.Errorf("expected 11 sinks, got %d: %v", len(cfg.Sinks), err)
+		}
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		dir := t.TempDir()
+		blocker := filepath.Join(dir, "file")
+		if err := os.WriteFile(blocker, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		l := NewLogger()
+		err := l.Configure(Config{Level: "debug", Sinks: []SinkConfig{
+			{Type: "file", Path: filepath.Join(dir, "app.log")},
+			{Type: "file", Path: filepath.Join(blocker, "app.log")},
+		}})
+		if err == nil || !strings.Contains(err.Error(), "sinks[1]") {
+			t.Errorf("expected error of the second sink, got %v", err)
+		}
+		if state := l.LevelState(); state.Level != "info" || len(state.Sinks) != 0 {
+			t.Errorf("expected logger to be unchanged, got %+v", state)
+		}
+	})
+}
+
+func TestConfigureWhileLogging(t *testing.T) {
+	l := NewLogger()
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { _ = r.Labels["app"] })
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for i := 0; i < 500; i++ {
+				l.Info("concurrent", "i", i)
+			}
+		}()
+	}
+	close(start)
+	for i := 0; i < 1000; i++ {
+		if err := l.Configure(Config{Level: "info", Labels: map[string]string{"app": "api"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,411 @@
+package gologger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables read by Config.ApplyEnv
+const EnvPrefix = "GOLOGGER_"
+
+// LoadConfig loads a config from a JSON or YAML file, chosen by its extension, and applies the GOLOGGER_*
+// environment variables on top, see Config.ApplyEnv. Without a path the config is read from the environment only.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".json":
+			cfg, err = ParseJSONConfig(data)
+		case ".yaml", ".yml":
+			cfg, err = ParseYAMLConfig(data)
+		default:
+			return cfg, fmt.Errorf("unknown config format %q of %s, expected .json, .yaml or .yml", ext, path)
+		}
+		if err != nil {
+			return cfg, fmt.Errorf("failed to load config %s: %w", path, err)
+		}
+	}
+	if err := cfg.ApplyEnv(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// ParseJSONConfig parses a JSON config document, unknown fields are reported as ConfigError
+func ParseJSONConfig(data []byte) (Config, error) {
+	var cfg Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return cfg, fmt.Errorf("invalid JSON config: %w", err)
+	}
+	return cfg, decodeConfig("", reflect.ValueOf(&cfg).Elem(), jsonScalars(doc))
+}
+
+// ParseYAMLConfig parses a YAML config document, unknown fields are reported as ConfigError
+func ParseYAMLConfig(data []byte) (Config, error) {
+	var cfg Config
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return cfg, fmt.Errorf("invalid YAML config: %w", err)
+	}
+	doc, err := yamlNode(&root)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid YAML config: %w", err)
+	}
+	return cfg, decodeConfig("", reflect.ValueOf(&cfg).Elem(), doc)
+}
+
+// ApplyEnv overrides the config with the GOLOGGER_* environment variables, named after the upper-cased field path
+// with underscores, e.g. GOLOGGER_LEVEL, GOLOGGER_STRINGERS_TIME_FORMAT or GOLOGGER_SINKS_0_MIN_LEVEL.
+// Maps are given as comma-separated pairs like GOLOGGER_MODULES=db=debug,http=warn.
+// Slice indices must be consecutive from the end of the current slice. Unknown variables with the prefix and sparse
+// indices are reported as ConfigError.
+func (c *Config) ApplyEnv() error {
+	return c.applyEnv(os.Environ())
+}
+
+// applyEnv applies the variables of environ in name order, comparing indices numerically
+func (c *Config) applyEnv(environ []string) error {
+	sort.Slice(environ, func(i, j int) bool { return envLess(environ[i], environ[j]) })
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		key, ok := strings.CutPrefix(name, EnvPrefix)
+		if !ok {
+			continue
+		}
+		doc := make(map[string]any)
+		ok, err := envNode(reflect.ValueOf(c).Elem(), doc, key, value)
+		if err == nil && !ok {
+			err = configFieldError(name, "unknown variable")
+		}
+		if err == nil {
+			err = decodeConfig("", reflect.ValueOf(c).Elem(), doc)
+		}
+		if err != nil {
+			if cfgErr, ok := err.(*ConfigError); ok {
+				cfgErr.Field = name
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// envLess orders variables by name, so GOLOGGER_SINKS_2 is applied before GOLOGGER_SINKS_10
+func envLess(a, b string) bool {
+	a, _, _ = strings.Cut(a, "=")
+	b, _, _ = strings.Cut(b, "=")
+	as, bs := strings.Split(a, "_"), strings.Split(b, "_")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr == nil && bErr == nil && an != bn {
+			return an < bn
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+// envNode stores the value of the variable key in doc at the path of the field of v it names.
+// A slice index may at most append one element to the current slice, earlier elements must be set first.
+func envNode(v reflect.Value, doc map[string]any, key, value string) (bool, error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := configFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		upper := strings.ToUpper(name)
+		if key == upper {
+			doc[name] = value
+			return true, nil
+		}
+		rest, ok := strings.CutPrefix(key, upper+"_")
+		if !ok {
+			continue
+		}
+
+		switch fv := v.Field(i); {
+		case fv.Kind() == reflect.Struct:
+			sub := make(map[string]any)
+			ok, err := envNode(fv, sub, rest, value)
+			if ok || err != nil {
+				doc[name] = sub
+				return ok, err
+			}
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Struct:
+			index, rest, ok := strings.Cut(rest, "_")
+			n, err := strconv.Atoi(index)
+			if !ok || err != nil || n < 0 || n > 1000 {
+				continue
+			}
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			if n < fv.Len() {
+				elem = fv.Index(n)
+			}
+			sub := make(map[string]any)
+			ok, err = envNode(elem, sub, rest, value)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				if n > fv.Len() {
+					return false, configFieldError(name, "%s[%d] is not set, indices must be consecutive", name, fv.Len())
+				}
+				items := make([]any, n+1)
+				items[n] = sub
+				doc[name] = items
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// configFieldName returns the JSON name of a config field, or "" if it is not loaded from documents
+func configFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" || !f.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// jsonScalars converts the numbers and booleans of a decoded JSON document to strings, like YAML and env scalars
+func jsonScalars(node any) any {
+	switch node := node.(type) {
+	case map[string]any:
+		for k, v := range node {
+			node[k] = jsonScalars(v)
+		}
+	case []any:
+		for i, v := range node {
+			node[i] = jsonScalars(v)
+		}
+	case json.Number:
+		return node.String()
+	case bool:
+		return strconv.FormatBool(node)
+	}
+	return node
+}
+
+// decodeConfig decodes a document of maps, slices and string scalars into v, path names v in errors.
+// Slice elements are decoded into existing elements, nil elements and values keep the current value.
+func decodeConfig(path string, v reflect.Value, node any) error {
+	if node == nil {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		doc, ok := node.(map[string]any)
+		if !ok {
+			return configFieldError(rootPath(path), "expected a mapping")
+		}
+		fields := make(map[string]int, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if name := configFieldName(v.Type().Field(i)); name != "" {
+				fields[name] = i
+			}
+		}
+		keys := make([]string, 0, len(doc))
+		for key := range doc {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			i, ok := fields[key]
+			if !ok {
+				return configFieldError(joinPath(path, key), "unknown field")
+			}
+			if err := decodeConfig(joinPath(path, key), v.Field(i), doc[key]); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		items, ok := node.([]any)
+		if !ok {
+			return configFieldError(path, "expected a sequence")
+		}
+		if len(items) > v.Len() {
+			grown := reflect.MakeSlice(v.Type(), len(items), len(items))
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		for i, item := range items {
+			if err := decodeConfig(fmt.Sprintf("%s[%d]", path, i), v.Index(i), item); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		switch node := node.(type) {
+		case map[string]any:
+			for key, value := range node {
+				s, ok := value.(string)
+				if !ok && value != nil {
+					return configFieldError(joinPath(path, key), "expected a scalar")
+				}
+				m.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(s))
+			}
+		case string:
+			for _, pair := range strings.Split(node, ",") {
+				if strings.TrimSpace(pair) == "" {
+					continue
+				}
+				key, value, ok := strings.Cut(pair, "=")
+				if !ok {
+					return configFieldError(path, "invalid pair %q: expected key=value", pair)
+				}
+				m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), reflect.ValueOf(strings.TrimSpace(value)))
+			}
+		default:
+			return configFieldError(path, "expected a mapping")
+		}
+		v.Set(m)
+
+	case reflect.String:
+		s, ok := node.(string)
+		if !ok {
+			return configFieldError(path, "expected a scalar")
+		}
+		v.SetString(s)
+
+	case reflect.Bool:
+		s, ok := node.(string)
+		if !ok {
+			return configFieldError(path, "expected a boolean")
+		}
+		b, err := parseConfigBool(s)
+		if err != nil {
+			return configFieldError(path, "invalid boolean %q", s)
+		}
+		v.SetBool(b)
+
+	default:
+		return configFieldError(path, "unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// parseConfigBool parses a boolean like strconv.ParseBool, also accepting the YAML 1.1 words yes, no, on and off
+func parseConfigBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// joinPath appends a key to a field path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// rootPath names the document itself in errors
+func rootPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+// yamlNode converts a YAML node into maps, slices, string scalars and nil, resolving aliases and merge keys
+func yamlNode(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case 0:
+		return nil, nil
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNode(n.Content[0])
+	case yaml.AliasNode:
+		return yamlNode(n.Alias)
+	case yaml.ScalarNode:
+		if n.ShortTag() == "!!null" {
+			return nil, nil
+		}
+		return n.Value, nil
+	case yaml.SequenceNode:
+		items := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			item, err := yamlNode(c)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		var merged []map[string]any
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			node, err := yamlNode(value)
+			if err != nil {
+				return nil, err
+			}
+			if key.ShortTag() == "!!merge" {
+				switch node := node.(type) {
+				case map[string]any:
+					merged = append(merged, node)
+				case []any:
+					for _, item := range node {
+						if item, ok := item.(map[string]any); ok {
+							merged = append(merged, item)
+						}
+					}
+				}
+				continue
+			}
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("yaml: line %d: expected a scalar key", key.Line)
+			}
+			if _, ok := m[key.Value]; ok {
+				return nil, fmt.Errorf("yaml: line %d: duplicate key %q", key.Line, key.Value)
+			}
+			m[key.Value] = node
+		}
+		// explicit keys win over merged ones, earlier merged mappings over later ones
+		for _, mm := range merged {
+			for k, v := range mm {
+				if _, ok := m[k]; !ok {
+					m[k] = v
+				}
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("yaml: line %d: unsupported node", n.Line)
+}
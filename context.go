@@ -0,0 +1,41 @@
+package gologger
+
+import "context"
+
+// ContextExtractor returns key-value pairs taken from a context, e.g. trace or tenant IDs
+type ContextExtractor func(ctx context.Context) []any
+
+// RegisterContextExtractor registers a function whose fields are appended to every record logged with a context
+func RegisterContextExtractor(extractor ContextExtractor) {
+	defaultLogger.RegisterContextExtractor(extractor)
+}
+
+// RegisterContextExtractor registers a function whose fields are appended to every record logged with a context
+func (l *Logger) RegisterContextExtractor(extractor ContextExtractor) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.extractors = append(l.extractors, extractor)
+}
+
+// WithContextExtractor registers a context extractor on the logger
+func WithContextExtractor(extractor ContextExtractor) Option {
+	return func(l *Logger) { l.extractors = append(l.extractors, extractor) }
+}
+
+// extractContext runs all registered extractors on ctx, the returned pairs are validated like regular args
+func (l *Logger) extractContext(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	extractors := make([]ContextExtractor, len(l.extractors))
+	copy(extractors, l.extractors)
+	l.mu.RUnlock()
+
+	var args []any
+	for _, extract := range extractors {
+		args = append(args, extract(ctx)...)
+	}
+	return l.validateArgs(args)
+}
@@ -1,6 +1,7 @@
 package gologger
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,10 @@ type DbConfig struct {
 	TimeFormat string
 	LabelsMap  map[string]string
 	MinLevel   *slog.Level
+	Processors []Processor
+	Sampling   *SamplingConfig
+	// SourceColumn adds a source column with the call site, see SetAddSource. Existing tables must be migrated manually.
+	SourceColumn bool
 }
 
 type dialectQueries struct {
@@ -21,9 +26,17 @@ type dialectQueries struct {
 	insertLogSQL   string
 }
 
-var db *sql.DB
+func getDialectQueries(dialect string, tableName string, withSource bool) (dialectQueries, error) {
+	// the optional source column is appended to the table and the insert
+	var sourceInsert string
+	sourceColumn := func(sqlType string) string { return "" }
+	sourceValue := func(placeholder string) string { return "" }
+	if withSource {
+		sourceInsert = ", source"
+		sourceColumn = func(sqlType string) string { return ",\n\t\t\t\t\tsource " + sqlType }
+		sourceValue = func(placeholder string) string { return ", " + placeholder }
+	}
 
-func getDialectQueries(dialect string, tableName string) (dialectQueries, error) {
 	switch dialect {
 	case "mysql":
 		return dialectQueries{
@@ -34,11 +47,11 @@ func getDialectQueries(dialect string, tableName string) (dialectQueries, error)
 					level VARCHAR(10) NOT NULL,
 					message TEXT NOT NULL,
 					labels JSON,
-					fields JSON
-				)`, tableName),
+					fields JSON%s
+				)`, tableName, sourceColumn("VARCHAR(512)")),
 			insertLogSQL: fmt.Sprintf(`
-				INSERT INTO %s (timestamp, level, message, labels, fields)
-				VALUES (?, ?, ?, ?, ?)`, tableName),
+				INSERT INTO %s (timestamp, level, message, labels, fields%s)
+				VALUES (?, ?, ?, ?, ?%s)`, tableName, sourceInsert, sourceValue("?")),
 		}, nil
 
 	case "postgres":
@@ -50,11 +63,11 @@ func getDialectQueries(dialect string, tableName string) (dialectQueries, error)
 					level VARCHAR(10) NOT NULL,
 					message TEXT NOT NULL,
 					labels JSONB,
-					fields JSONB
-				)`, tableName),
+					fields JSONB%s
+				)`, tableName, sourceColumn("TEXT")),
 			insertLogSQL: fmt.Sprintf(`
-				INSERT INTO %s (timestamp, level, message, labels, fields)
-				VALUES ($1, $2, $3, $4, $5)`, tableName),
+				INSERT INTO %s (timestamp, level, message, labels, fields%s)
+				VALUES ($1, $2, $3, $4, $5%s)`, tableName, sourceInsert, sourceValue("$6")),
 		}, nil
 
 	case "sqlite":
@@ -66,11 +79,11 @@ func getDialectQueries(dialect string, tableName string) (dialectQueries, error)
 					level VARCHAR(10) NOT NULL,
 					message TEXT NOT NULL,
 					labels TEXT,
-					fields TEXT
-				)`, tableName),
+					fields TEXT%s
+				)`, tableName, sourceColumn("TEXT")),
 			insertLogSQL: fmt.Sprintf(`
-				INSERT INTO %s (timestamp, level, message, labels, fields)
-				VALUES (?, ?, ?, ?, ?)`, tableName),
+				INSERT INTO %s (timestamp, level, message, labels, fields%s)
+				VALUES (?, ?, ?, ?, ?%s)`, tableName, sourceInsert, sourceValue("?")),
 		}, nil
 
 	case "mssql":
@@ -83,11 +96,11 @@ func getDialectQueries(dialect string, tableName string) (dialectQueries, error)
 					level VARCHAR(10) NOT NULL,
 					message TEXT NOT NULL,
 					labels NVARCHAR(MAX),
-					fields NVARCHAR(MAX)
-				)`, tableName, tableName),
+					fields NVARCHAR(MAX)%s
+				)`, tableName, tableName, sourceColumn("NVARCHAR(512)")),
 			insertLogSQL: fmt.Sprintf(`
-				INSERT INTO %s (timestamp, level, message, labels, fields)
-				VALUES (@p1, @p2, @p3, @p4, @p5)`, tableName),
+				INSERT INTO %s (timestamp, level, message, labels, fields%s)
+				VALUES (@p1, @p2, @p3, @p4, @p5%s)`, tableName, sourceInsert, sourceValue("@p6")),
 		}, nil
 
 	default:
@@ -95,26 +108,32 @@ func getDialectQueries(dialect string, tableName string) (dialectQueries, error)
 	}
 }
 
-func setupDbLogger(cfg DbConfig, dialect string) error {
+// DbSink is a handle to a database sink set up with one of the UseXDb functions
+type DbSink struct {
+	registration
+	cfg     DbConfig
+	queries dialectQueries
+	ownsDB  bool // the connection was opened by Configure and is closed with the sink
+}
+
+func (l *Logger) setupDbLogger(cfg DbConfig, dialect string) (*DbSink, error) {
 	if cfg.DB == nil {
-		return fmt.Errorf("database connection cannot be nil")
+		return nil, fmt.Errorf("database connection cannot be nil")
 	}
 
 	if cfg.TableName == "" {
-		return fmt.Errorf("table name cannot be empty")
+		return nil, fmt.Errorf("table name cannot be empty")
 	}
 
-	queries, err := getDialectQueries(dialect, cfg.TableName)
+	queries, err := getDialectQueries(dialect, cfg.TableName, cfg.SourceColumn)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if _, err := cfg.DB.Exec(queries.createTableSQL); err != nil {
-		return fmt.Errorf("failed to create log table: %w", err)
+		return nil, fmt.Errorf("failed to create log table: %w", err)
 	}
 
-	db = cfg.DB
-
 	if cfg.TimeFormat == "" {
 		cfg.TimeFormat = time.RFC3339
 	}
@@ -123,70 +142,110 @@ func setupDbLogger(cfg DbConfig, dialect string) error {
 		cfg.LabelsMap = make(map[string]string)
 	}
 
-	writeToDb := func(level slog.Level, msg string, args ...any) {
-		timestamp := time.Now().Format(cfg.TimeFormat)
+	minLevel := slog.LevelDebug
+	if cfg.MinLevel != nil {
+		minLevel = *cfg.MinLevel
+	}
+	sink := &DbSink{cfg: cfg, queries: queries}
+	name := fmt.Sprintf("%s database table %s", dialect, cfg.TableName)
+	sink.remove = l.RegisterSink(name, minLevel, sink, cfg.Processors...)
+	if cfg.Sampling != nil {
+		l.SetSinkSampling(name, cfg.Sampling)
+	}
 
-		// Convert labels to JSON string
-		labelsJSON, err := json.Marshal(cfg.LabelsMap)
-		if err != nil {
-			slog.Error("Failed to marshal labels to JSON", "error", err, "labels", cfg.LabelsMap)
-			return
-		}
+	return sink, nil
+}
 
-		// Parse args into fields map and convert to JSON
-		fields := make(map[string]any)
-		for i := 0; i < len(args); i += 2 {
-			if i+1 < len(args) {
-				fields[fmt.Sprint(args[i])] = args[i+1]
-			}
-		}
-		fieldsJSON, err := json.Marshal(fields)
-		if err != nil {
-			slog.Error("Failed to marshal fields to JSON", "error", err, "fields", fields)
-			return
-		}
+// Handle inserts a log entry into the table
+func (s *DbSink) Handle(r Record) {
+	timestamp := r.Time.Format(s.cfg.TimeFormat)
 
-		_, err = db.Exec(queries.insertLogSQL,
-			timestamp,
-			levelToString(level),
-			msg,
-			string(labelsJSON),
-			string(fieldsJSON),
-		)
-		if err != nil {
-			slog.Error("Failed to write to database", "error", err, "message", msg, "level", levelToString(level))
-		}
+	// Convert labels to JSON string
+	labelsMap := mergeLabels(s.cfg.LabelsMap, r.Labels)
+	labelsJSON, err := json.Marshal(labelsMap)
+	if err != nil {
+		fallbackLogger().Error("Failed to marshal labels to JSON", "error", err, "labels", labelsMap)
+		return
 	}
 
-	minLevel := slog.LevelDebug
-	if cfg.MinLevel != nil {
-		minLevel = *cfg.MinLevel
+	// Parse args into fields map and convert to JSON
+	fields := fieldsToMap(r.Fields)
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		fallbackLogger().Error("Failed to marshal fields to JSON", "error", err, "fields", fields)
+		return
 	}
-	levelsToRegister := getLevelsAbove(minLevel)
-	for idx := range levelsToRegister {
-		level := levelsToRegister[idx]
-		RegisterCallback(level, func(msg string, args ...any) { writeToDb(level, msg, args...) })
+
+	values := []any{
+		timestamp,
+		levelToString(r.Level),
+		r.Message,
+		string(labelsJSON),
+		string(fieldsJSON),
 	}
+	if s.cfg.SourceColumn {
+		var src *string
+		if r.Source != nil {
+			caller := formatSource(r.Source)
+			src = &caller
+		}
+		values = append(values, src)
+	}
+
+	_, err = s.cfg.DB.Exec(s.queries.insertLogSQL, values...)
+	if err != nil {
+		fallbackLogger().Error("Failed to write to database", "error", err, "message", r.Message, "level", levelToString(r.Level))
+	}
+}
+
+// Flush is a no-op, every log is written synchronously
+func (s *DbSink) Flush(_ context.Context) error { return nil }
 
+// Close removes the sink from its logger. The database connection is owned by the caller and stays open,
+// unless it was opened by Configure.
+func (s *DbSink) Close(_ context.Context) error {
+	if s.unregister() && s.ownsDB {
+		return s.cfg.DB.Close()
+	}
 	return nil
 }
 
 // UseMysqlDb sets up logging to a MySQL database
-func UseMysqlDb(cfg DbConfig) error {
-	return setupDbLogger(cfg, "mysql")
+func UseMysqlDb(cfg DbConfig) (*DbSink, error) {
+	return defaultLogger.UseMysqlDb(cfg)
+}
+
+// UseMysqlDb sets up logging on the logger to a MySQL database
+func (l *Logger) UseMysqlDb(cfg DbConfig) (*DbSink, error) {
+	return l.setupDbLogger(cfg, "mysql")
 }
 
 // UsePostgresDb sets up logging to a PostgreSQL database
-func UsePostgresDb(cfg DbConfig) error {
-	return setupDbLogger(cfg, "postgres")
+func UsePostgresDb(cfg DbConfig) (*DbSink, error) {
+	return defaultLogger.UsePostgresDb(cfg)
+}
+
+// UsePostgresDb sets up logging on the logger to a PostgreSQL database
+func (l *Logger) UsePostgresDb(cfg DbConfig) (*DbSink, error) {
+	return l.setupDbLogger(cfg, "postgres")
 }
 
 // UseSqlite sets up logging to a SQLite database
-func UseSqlite(cfg DbConfig) error {
-	return setupDbLogger(cfg, "sqlite")
+func UseSqlite(cfg DbConfig) (*DbSink, error) {
+	return defaultLogger.UseSqlite(cfg)
+}
+
+// UseSqlite sets up logging on the logger to a SQLite database
+func (l *Logger) UseSqlite(cfg DbConfig) (*DbSink, error) {
+	return l.setupDbLogger(cfg, "sqlite")
 }
 
 // UseMssqlDb sets up logging to a Microsoft SQL Server database
-func UseMssqlDb(cfg DbConfig) error {
-	return setupDbLogger(cfg, "mssql")
+func UseMssqlDb(cfg DbConfig) (*DbSink, error) {
+	return defaultLogger.UseMssqlDb(cfg)
+}
+
+// UseMssqlDb sets up logging on the logger to a Microsoft SQL Server database
+func (l *Logger) UseMssqlDb(cfg DbConfig) (*DbSink, error) {
+	return l.setupDbLogger(cfg, "mssql")
 }
@@ -0,0 +1,202 @@
+package gologger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupConfig configures the suppression of repeated records, see EnableDedup
+type DedupConfig struct {
+	Window time.Duration // Time after the first occurrence in which repeats are suppressed, defaults to one second
+	Keys   []string      // Field keys that are part of the fingerprint besides level and message, dotted for grouped fields; Lazy values are ignored
+}
+
+// dedupper suppresses records whose fingerprint was seen within the window
+type dedupper struct {
+	l       *Logger
+	cfg     DedupConfig
+	mu      sync.Mutex
+	pending map[string]*repeats
+	closed  bool
+}
+
+// repeats tracks the repetitions of a fingerprint within its window
+type repeats struct {
+	record Record // first occurrence
+	count  int    // suppressed repetitions
+	last   time.Time
+	timer  *time.Timer
+}
+
+// EnableDedup suppresses repeated records of the default logger, see Logger.EnableDedup
+func EnableDedup(cfg DedupConfig) { defaultLogger.EnableDedup(cfg) }
+
+// EnableDedup suppresses repeated records of the logger, replacing an earlier configuration.
+// The first record of a fingerprint is passed on immediately, repeats within the window are counted and
+// reported in a single "message repeated N times" record when the window closes or the logger shuts down.
+func (l *Logger) EnableDedup(cfg DedupConfig) {
+	d := l.newDedupper(cfg)
+	l.mu.Lock()
+	previous := l.dedup
+	l.dedup = d
+	l.mu.Unlock()
+
+	previous.close()
+}
+
+// DisableDedup stops suppressing repeated records of the default logger
+func DisableDedup() { defaultLogger.DisableDedup() }
+
+// DisableDedup stops suppressing repeated records of the logger and reports the pending repeats
+func (l *Logger) DisableDedup() {
+	l.mu.Lock()
+	previous := l.dedup
+	l.dedup = nil
+	l.mu.Unlock()
+
+	previous.close()
+}
+
+// WithDedup enables deduplication on the logger, see EnableDedup
+func WithDedup(cfg DedupConfig) Option {
+	return func(l *Logger) { l.dedup = l.newDedupper(cfg) }
+}
+
+func (l *Logger) newDedupper(cfg DedupConfig) *dedupper {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Second
+	}
+	return &dedupper{l: l, cfg: cfg, pending: make(map[string]*repeats)}
+}
+
+// first reports whether the record is the first of its fingerprint in the current window, repeats are counted.
+// The first record is resolved, see resolveLazy, before it is stored for the summary and returned. Repeats arriving
+// in the meantime are counted already.
+func (d *dedupper) first(r Record, resolve func(Record) Record) (Record, bool) {
+	key := d.fingerprint(r)
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return resolve(r), true
+	}
+	if rep, ok := d.pending[key]; ok {
+		rep.count++
+		rep.last = r.Time
+		d.mu.Unlock()
+		return r, false
+	}
+	rep := &repeats{}
+	d.pending[key] = rep
+	d.mu.Unlock()
+
+	// resolve outside the lock, Lazy values and stringers may log themselves
+	r = resolve(r)
+
+	d.mu.Lock()
+	rep.record = r
+	pending := d.pending[key] == rep
+	if pending {
+		rep.timer = time.AfterFunc(d.cfg.Window, func() { d.expire(key, rep) })
+	}
+	d.mu.Unlock()
+
+	if !pending {
+		// closed while resolving, close skipped the repeats without a timer
+		d.report(rep)
+	}
+	return r, true
+}
+
+// fingerprint identifies records by level, message and the configured keys
+func (d *dedupper) fingerprint(r Record) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\x00%s", r.Level, r.Message)
+	if len(d.cfg.Keys) > 0 {
+		fields := fieldsToMap(r.Fields)
+		for _, key := range d.cfg.Keys {
+			v, _ := lookupField(fields, key)
+			if _, ok := v.(lazyValue); ok {
+				// Lazy values are not resolved for repeats, so they are not part of the fingerprint
+				v = nil
+			}
+			fmt.Fprintf(&b, "\x00%v", v)
+		}
+	}
+	return b.String()
+}
+
+// lookupField finds a value in a fields map by its dotted key
+func lookupField(fields map[string]any, key string) (any, bool) {
+	if v, ok := fields[key]; ok {
+		return v, true
+	}
+	group, rest, found := strings.Cut(key, ".")
+	if !found {
+		return nil, false
+	}
+	nested, ok := fields[group].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookupField(nested, rest)
+}
+
+// expire ends the window of a fingerprint and reports its repeats
+func (d *dedupper) expire(key string, rep *repeats) {
+	d.mu.Lock()
+	if d.pending[key] != rep {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	d.mu.Unlock()
+
+	d.report(rep)
+}
+
+// report logs a summary of the suppressed repeats, bypassing sampling and deduplication
+func (d *dedupper) report(rep *repeats) {
+	if rep.count == 0 {
+		return
+	}
+
+	r := rep.record.Add("repeated", rep.count, "first", rep.record.Time, "last", rep.last)
+	r.Message = fmt.Sprintf("message repeated %d times: %s", rep.count, rep.record.Message)
+	r.Time = time.Now()
+
+	d.l.mu.RLock()
+	callbacks, _ := d.l.matchingCallbacks(r.Level)
+	d.l.mu.RUnlock()
+	d.l.dispatch(callbacks, r, false, true)
+}
+
+// close stops all windows and reports their repeats
+func (d *dedupper) close() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	d.closed = true
+	pending := d.pending
+	d.pending = make(map[string]*repeats)
+	d.mu.Unlock()
+
+	reps := make([]*repeats, 0, len(pending))
+	for _, rep := range pending {
+		if rep.timer == nil {
+			// still resolving its first record, first reports it
+			continue
+		}
+		rep.timer.Stop()
+		reps = append(reps, rep)
+	}
+	sort.Slice(reps, func(i, j int) bool { return reps[i].record.Time.Before(reps[j].record.Time) })
+	for _, rep := range reps {
+		d.report(rep)
+	}
+}
@@ -0,0 +1,65 @@
+package gologger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	t.Run("summary when window closes", func(t *testing.T) {
+		l := NewLogger(WithDedup(DedupConfig{Window: 20 * time.Millisecond, Keys: []string{"db.host"}}))
+
+		received := make(chan Record, 10)
+		l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { received <- r })
+
+		for i := 0; i < 5; i++ {
+			l.WithGroup("db").Error("connection failed", "host", "a")
+		}
+		l.WithGroup("db").Error("connection failed", "host", "b")
+
+		for _, host := range []string{"a", "b"} {
+			r := <-received
+			if r.Message != "connection failed" || fieldsToMap(r.Fields)["db"].(map[string]any)["host"] != host {
+				t.Errorf("expected first occurrence for host %s, got %q %v", host, r.Message, r.Fields)
+			}
+		}
+
+		select {
+		case r := <-received:
+			fields := fieldsToMap(r.Fields)
+			if r.Message != "message repeated 4 times: connection failed" || fields["repeated"] != 4 {
+				t.Errorf("unexpected summary %q %v", r.Message, fields)
+			}
+			if first, last := fields["first"].(time.Time), fields["last"].(time.Time); last.Before(first) {
+				t.Errorf("expected last %s after first %s", last, first)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected summary after the window closed")
+		}
+
+		// a new window starts after the summary
+		l.WithGroup("db").Error("connection failed", "host", "a")
+		if r := <-received; r.Message != "connection failed" {
+			t.Errorf("expected a new first occurrence, got %q", r.Message)
+		}
+	})
+
+	t.Run("summary on shutdown", func(t *testing.T) {
+		l := NewLogger(WithDedup(DedupConfig{Window: time.Hour}))
+
+		var events []string
+		sink := &testSink{name: "sink", events: &events}
+		l.RegisterSink("sink", slog.LevelInfo, sink)
+		l.Warn("retrying")
+		l.Warn("retrying")
+
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if len(sink.records) != 2 || sink.records[1].Message != "message repeated 1 times: retrying" {
+			t.Errorf("expected summary before the sink was closed, got %v", sink.records)
+		}
+	})
+}
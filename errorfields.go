@@ -0,0 +1,103 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// StackKey is the field key of the stack trace added by ErrorConfig.StackLevel
+const StackKey = "stack"
+
+// ErrorConfig configures how error values and stack traces are added to records
+type ErrorConfig struct {
+	Chain      bool        // Add a <key>_chain field with the messages of all wrapped errors, including errors.Join branches
+	StackLevel *slog.Level // Add a stack field with the trace of the logging call to records at or above this level, nil disables it
+}
+
+// SetErrorConfig configures error rendering of the default logger, see Logger.SetErrorConfig
+func SetErrorConfig(cfg ErrorConfig) { defaultLogger.SetErrorConfig(cfg) }
+
+// SetErrorConfig configures error rendering of the logger. Error values are always rendered as their message,
+// e.g. the error field of the JSON file sink, the config adds their chain and stack traces.
+func (l *Logger) SetErrorConfig(cfg ErrorConfig) {
+	l.mu.Lock()
+	l.errors = cfg
+	l.mu.Unlock()
+}
+
+// WithErrorConfig configures error rendering of the logger, see SetErrorConfig
+func WithErrorConfig(cfg ErrorConfig) Option {
+	return func(l *Logger) { l.errors = cfg }
+}
+
+// addErrorFields appends the chains of error values and the stack trace to the fields
+func addErrorFields(fields []any, level slog.Level, cfg ErrorConfig) []any {
+	var extra []any
+	if cfg.Chain {
+		for i := 0; i+1 < len(fields); i += 2 {
+			if err, ok := fields[i+1].(error); ok && err != nil {
+				if chain := errorChain(err); len(chain) > 0 {
+					extra = append(extra, fmt.Sprint(fields[i])+"_chain", chain)
+				}
+			}
+		}
+	}
+	if cfg.StackLevel != nil && level >= *cfg.StackLevel {
+		extra = append(extra, StackKey, stackTrace())
+	}
+	return append(fields, extra...)
+}
+
+// errorChain returns the messages of all errors wrapped by err, depth first, like errors.Is traverses them
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			if inner := wrapped.Unwrap(); inner != nil {
+				chain = append(chain, inner.Error())
+				walk(inner)
+			}
+		case interface{ Unwrap() []error }:
+			for _, inner := range wrapped.Unwrap() {
+				if inner != nil {
+					chain = append(chain, inner.Error())
+					walk(inner)
+				}
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// stackTrace formats the stack of the current goroutine, starting at the logging call
+func stackTrace() string {
+	var pcs [64]uintptr
+	// skip runtime.Callers and stackTrace
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !isInternal(frame) {
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// errorValue renders error values as their message, other values are returned unchanged
+func errorValue(v any) any {
+	if err, ok := v.(error); ok && err != nil {
+		return err.Error()
+	}
+	return v
+}
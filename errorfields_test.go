@@ -0,0 +1,47 @@
+package gologger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestErrorFields(t *testing.T) {
+	stackLevel := slog.LevelError
+	l := NewLogger(WithErrorConfig(ErrorConfig{Chain: true, StackLevel: &stackLevel}))
+
+	var records []Record
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { records = append(records, r) })
+
+	root := errors.New("connection refused")
+	err := fmt.Errorf("query users: %w", errors.Join(root, errors.New("retry budget exhausted")))
+	l.Warn("degraded", "error", err)
+	l.Error("failed", "error", err)
+
+	warn := fieldsToMap(records[0].Fields)
+	if warn["error"] != err.Error() {
+		t.Errorf("expected error message, got %#v", warn["error"])
+	}
+	chain, _ := warn["error_chain"].([]string)
+	expected := []string{"connection refused\nretry budget exhausted", "connection refused", "retry budget exhausted"}
+	if fmt.Sprint(chain) != fmt.Sprint(expected) {
+		t.Errorf("expected chain %q, got %q", expected, chain)
+	}
+	if _, ok := warn[StackKey]; ok {
+		t.Error("expected no stack below the stack level")
+	}
+
+	stack, _ := fieldsToMap(records[1].Fields)[StackKey].(string)
+	if !strings.HasPrefix(stack, "github.com/FrauElster/gologger/v2.TestErrorFields") {
+		t.Errorf("expected stack to start at the test function, got %q", stack)
+	}
+
+	// errors used to be marshalled as {}
+	data, _ := json.Marshal(fieldsToMap([]any{"error", root}))
+	if string(data) != `{"error":"connection refused"}` {
+		t.Errorf("expected error message in JSON, got %s", data)
+	}
+}
@@ -0,0 +1,54 @@
+package gologger
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrorHandler receives internal errors of a logger, e.g. invalid arguments in ValidationRepair mode
+type ErrorHandler func(err error)
+
+// SetErrorHandler sets the handler for internal errors of the default logger
+func SetErrorHandler(h ErrorHandler) { defaultLogger.SetErrorHandler(h) }
+
+// SetErrorHandler sets the handler for internal errors of the logger.
+// Without a handler, internal errors are written to the console.
+func (l *Logger) SetErrorHandler(h ErrorHandler) {
+	l.mu.Lock()
+	l.onError = h
+	l.mu.Unlock()
+}
+
+// WithErrorHandler sets the handler for internal errors of the logger
+func WithErrorHandler(h ErrorHandler) Option {
+	return func(l *Logger) { l.onError = h }
+}
+
+// reportError passes an internal error to the error handler
+func (l *Logger) reportError(err error) {
+	l.mu.RLock()
+	onError := l.onError
+	l.mu.RUnlock()
+
+	if onError == nil {
+		fallbackLogger().Error("gologger internal error", "error", err)
+		return
+	}
+	onError(err)
+}
+
+// PanicError is reported to the ErrorHandler when a callback, sink or stringer panics.
+// The panic is recovered, so the remaining callbacks still receive the record.
+type PanicError struct {
+	Source string // identity of the panicking callback, sink or stringer
+	Value  any    // value passed to panic
+	Stack  []byte // stack trace of the panicking goroutine
+}
+
+func newPanicError(source string, value any) *PanicError {
+	return &PanicError{Source: source, Value: value, Stack: debug.Stack()}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Source, e.Value)
+}
@@ -0,0 +1,51 @@
+package gologger
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// exitFunc terminates the process after Fatal, replaced in tests
+var exitFunc = os.Exit
+
+// fatalFlushTimeout bounds how long Fatal and Panic wait for queued records before giving up
+const fatalFlushTimeout = 5 * time.Second
+
+// Fatal logs a message at LevelFatal, flushes every sink and exits the process with status 1
+func Fatal(msg string, args ...any) {
+	defaultLogger.log(context.Background(), LevelFatal, msg, args...)
+	defaultLogger.flushBeforeExit()
+	exitFunc(1)
+}
+
+// Panic logs a message at LevelPanic, flushes every sink and panics with the message
+func Panic(msg string, args ...any) {
+	defaultLogger.log(context.Background(), LevelPanic, msg, args...)
+	defaultLogger.flushBeforeExit()
+	panic(msg)
+}
+
+// Fatal logs a message at LevelFatal, flushes every sink and exits the process with status 1
+func (l *Logger) Fatal(msg string, args ...any) {
+	l.log(context.Background(), LevelFatal, msg, args...)
+	l.flushBeforeExit()
+	exitFunc(1)
+}
+
+// Panic logs a message at LevelPanic, flushes every sink and panics with the message
+func (l *Logger) Panic(msg string, args ...any) {
+	l.log(context.Background(), LevelPanic, msg, args...)
+	l.flushBeforeExit()
+	panic(msg)
+}
+
+// flushBeforeExit drains the queues and flushes every sink, reporting failures as internal errors
+func (l *Logger) flushBeforeExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+	defer cancel()
+
+	if err := l.Flush(ctx); err != nil {
+		l.reportError(err)
+	}
+}
@@ -0,0 +1,67 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// fieldsToMap parses key-value pairs into a fields map, groups become nested maps
+func fieldsToMap(args []any) map[string]any {
+	fields := make(map[string]any)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fields[fmt.Sprint(args[i])] = fieldValue(args[i+1])
+		}
+	}
+	return fields
+}
+
+// fieldValue unpacks slog values and renders errors as their message, so they are marshalled as their contents
+func fieldValue(v any) any {
+	if lv, ok := lazyOf(v); ok {
+		// only unresolved while the record is processed and deduplicated, which must not force it
+		return lv
+	}
+	sv, ok := v.(slog.Value)
+	if !ok {
+		return errorValue(v)
+	}
+
+	sv = sv.Resolve()
+	if sv.Kind() != slog.KindGroup {
+		return errorValue(sv.Any())
+	}
+
+	group := make(map[string]any)
+	for _, a := range sv.Group() {
+		group[a.Key] = fieldValue(a.Value)
+	}
+	return group
+}
+
+// formatFields formats key-value pairs as " key=value", groups are flattened into dotted keys
+func formatFields(args []any) string {
+	var b strings.Builder
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			writeField(&b, fmt.Sprint(args[i]), args[i+1])
+		}
+	}
+	return b.String()
+}
+
+// writeField writes a single " key=value" pair, recursing into groups
+func writeField(b *strings.Builder, key string, v any) {
+	if sv, ok := v.(slog.Value); ok {
+		sv = sv.Resolve()
+		if sv.Kind() == slog.KindGroup {
+			for _, a := range sv.Group() {
+				writeField(b, key+"."+a.Key, a.Value)
+			}
+			return
+		}
+		v = sv.Any()
+	}
+	fmt.Fprintf(b, " %s=%v", key, v)
+}
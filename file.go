@@ -1,6 +1,7 @@
 package gologger
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -17,6 +18,8 @@ type FileConfig struct {
 	FormatJson bool              // Whether to format logs as JSON
 	LabelsMap  map[string]string // Labels to be included with every log entry
 	MinLevel   *slog.Level       // Minimum log level to write to file
+	Processors []Processor       // Processors that only apply to this sink
+	Sampling   *SamplingConfig   // Sampling that replaces the logger's sampling for this sink
 }
 
 type jsonLogEntry struct {
@@ -25,33 +28,39 @@ type jsonLogEntry struct {
 	Labels  map[string]string `json:"labels,omitempty"`
 	Message string            `json:"message"`
 	Fields  map[string]any    `json:"fields,omitempty"`
+	Caller  string            `json:"caller,omitempty"`
 }
 
-var (
-	fileWriter *os.File
-	fileMu     sync.Mutex
-)
+// FileSink is a handle to a log file set up with UseFile
+type FileSink struct {
+	registration
+	l   *Logger
+	cfg FileConfig
+	f   *os.File
+	mu  sync.Mutex
+}
 
 // UseFile sets up logging callbacks that write logs to the specified file
-func UseFile(cfg FileConfig) error {
+func UseFile(cfg FileConfig) (*FileSink, error) { return defaultLogger.UseFile(cfg) }
+
+// UseFile sets up logging callbacks on the logger that write logs to the specified file
+func (l *Logger) UseFile(cfg FileConfig) (*FileSink, error) {
 	if cfg.Path == "" {
-		return fmt.Errorf("file path cannot be empty")
+		return nil, fmt.Errorf("file path cannot be empty")
 	}
 
 	// Ensure directory exists
 	dir := filepath.Dir(cfg.Path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
 	// Open file in append mode, create if not exists
 	f, err := os.OpenFile(cfg.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to open log file %s: %w", cfg.Path, err)
+		return nil, fmt.Errorf("failed to open log file %s: %w", cfg.Path, err)
 	}
 
-	fileWriter = f
-
 	if cfg.TimeFormat == "" {
 		cfg.TimeFormat = time.RFC3339
 	}
@@ -60,106 +69,136 @@ func UseFile(cfg FileConfig) error {
 		cfg.LabelsMap = make(map[string]string)
 	}
 
-	// Helper function to write a log entry to file
-	writeToFile := func(level slog.Level, msg string, args ...any) {
-		timestamp := time.Now().Format(cfg.TimeFormat)
-
-		var logLine string
-		if cfg.FormatJson {
-			// Create JSON entry
-			entry := jsonLogEntry{
-				Time:    timestamp,
-				Level:   levelToString(level),
-				Message: msg,
-			}
+	sink := &FileSink{l: l, cfg: cfg, f: f}
+	l.mu.Lock()
+	l.file = sink
+	l.mu.Unlock()
 
-			// Add labels if present
-			if len(cfg.LabelsMap) > 0 {
-				entry.Labels = cfg.LabelsMap
-			}
+	minLevel := slog.LevelDebug
+	if cfg.MinLevel != nil {
+		minLevel = *cfg.MinLevel
+	}
+	sink.remove = l.RegisterSink("file "+cfg.Path, minLevel, sink, cfg.Processors...)
+	if cfg.Sampling != nil {
+		l.SetSinkSampling("file "+cfg.Path, cfg.Sampling)
+	}
 
-			// Parse args into fields map
-			if len(args) > 0 {
-				fields := make(map[string]any)
-				for i := 0; i < len(args); i += 2 {
-					if i+1 < len(args) {
-						fields[fmt.Sprint(args[i])] = args[i+1]
-					}
-				}
-				if len(fields) > 0 {
-					entry.Fields = fields
-				}
-			}
+	return sink, nil
+}
 
-			// Marshal to JSON
-			jsonData, err := json.Marshal(entry)
-			if err != nil {
-				slog.Error("Failed to marshal log entry to JSON",
-					"error", err,
-					"message", msg,
-					"level", levelToString(level))
-				return
-			}
-			logLine = string(jsonData) + "\n"
-		} else {
-			// Format text entry with labels
-			var labels string
-			if len(cfg.LabelsMap) > 0 {
-				labelPairs := make([]string, 0, len(cfg.LabelsMap))
-				for k, v := range cfg.LabelsMap {
-					labelPairs = append(labelPairs, fmt.Sprintf("%s=%s", k, v))
-				}
-				labels = fmt.Sprintf("[%s] ", strings.Join(labelPairs, " "))
-			}
+// Handle writes a log entry to the file
+func (s *FileSink) Handle(r Record) {
+	timestamp := r.Time.Format(s.cfg.TimeFormat)
+	labelsMap := mergeLabels(s.cfg.LabelsMap, r.Labels)
+
+	var logLine string
+	if s.cfg.FormatJson {
+		// Create JSON entry
+		entry := jsonLogEntry{
+			Time:    timestamp,
+			Level:   levelToString(r.Level),
+			Message: r.Message,
+		}
+		if r.Source != nil {
+			entry.Caller = formatSource(r.Source)
+		}
 
-			// Format fields
-			var fields string
-			for i := 0; i < len(args); i += 2 {
-				if i+1 < len(args) {
-					fields += fmt.Sprintf(" %v=%v", args[i], args[i+1])
-				}
-			}
+		// Add labels if present
+		if len(labelsMap) > 0 {
+			entry.Labels = labelsMap
+		}
 
-			logLine = fmt.Sprintf("[%s] %s: %s%s%s\n",
-				timestamp,
-				levelToString(level),
-				labels,
-				msg,
-				fields,
-			)
+		// Parse args into fields map
+		if len(r.Fields) > 0 {
+			fields := fieldsToMap(r.Fields)
+			if len(fields) > 0 {
+				entry.Fields = fields
+			}
 		}
 
-		// Write to file with mutex lock
-		fileMu.Lock()
-		if _, err := fileWriter.WriteString(logLine); err != nil {
-			// If file writing fails, log to stderr via slog
-			slog.Error("Failed to write to log file",
+		// Marshal to JSON
+		jsonData, err := json.Marshal(entry)
+		if err != nil {
+			fallbackLogger().Error("Failed to marshal log entry to JSON",
 				"error", err,
-				"message", msg,
-				"level", levelToString(level))
+				"message", r.Message,
+				"level", levelToString(r.Level))
+			return
+		}
+		logLine = string(jsonData) + "\n"
+	} else {
+		// Format text entry with labels
+		var labels string
+		if len(labelsMap) > 0 {
+			labelPairs := make([]string, 0, len(labelsMap))
+			for k, v := range labelsMap {
+				labelPairs = append(labelPairs, fmt.Sprintf("%s=%s", k, v))
+			}
+			labels = fmt.Sprintf("[%s] ", strings.Join(labelPairs, " "))
+		}
+
+		// Format fields
+		fields := formatFields(r.Fields)
+		if r.Source != nil {
+			fields += " caller=" + formatSource(r.Source)
 		}
-		fileMu.Unlock()
+
+		logLine = fmt.Sprintf("[%s] %s: %s%s%s\n",
+			timestamp,
+			levelToString(r.Level),
+			labels,
+			r.Message,
+			fields,
+		)
 	}
 
-	minLevel := slog.LevelDebug
-	if cfg.MinLevel != nil {
-		minLevel = *cfg.MinLevel
+	// Write to file with mutex lock
+	s.mu.Lock()
+	if _, err := s.f.WriteString(logLine); err != nil {
+		// If file writing fails, log to stderr via slog
+		fallbackLogger().Error("Failed to write to log file",
+			"error", err,
+			"message", r.Message,
+			"level", levelToString(r.Level))
 	}
-	levelsToRegister := getLevelsAbove(minLevel)
-	for idx := range levelsToRegister {
-		level := levelsToRegister[idx]
-		RegisterCallback(level, func(msg string, args ...any) { writeToFile(level, msg, args...) })
+	s.mu.Unlock()
+}
+
+// Close removes the sink from its logger and closes the log file
+func (s *FileSink) Close(_ context.Context) error {
+	if !s.unregister() {
+		return nil
 	}
 
-	return nil
+	s.l.mu.Lock()
+	if s.l.file == s {
+		s.l.file = nil
+	}
+	s.l.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// Flush commits the written logs to stable storage
+func (s *FileSink) Flush(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Sync()
 }
 
 // StopFile closes the file writer
-func StopFile() error {
-	if fileWriter != nil {
-		fileMu.Lock()
-		defer fileMu.Unlock()
-		return fileWriter.Close()
+func StopFile() error { return defaultLogger.StopFile() }
+
+// StopFile closes the file writer of the logger
+func (l *Logger) StopFile() error {
+	l.mu.RLock()
+	sink := l.file
+	l.mu.RUnlock()
+
+	if sink != nil {
+		return sink.Close(context.Background())
 	}
 	return nil
 }
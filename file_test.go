@@ -0,0 +1,48 @@
+package gologger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileSink(t *testing.T) {
+	l := NewLogger()
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	first, err := l.UseFile(FileConfig{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("first")
+	if err := first.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Close(context.Background()); err != nil {
+		t.Errorf("expected second close to be a no-op, got %v", err)
+	}
+
+	second, err := l.UseFile(FileConfig{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("second", "key", "value")
+	if err := second.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("third")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), data)
+	}
+	if !strings.HasSuffix(lines[0], "info: first") || !strings.HasSuffix(lines[1], "info: second key=value") {
+		t.Errorf("unexpected lines %q", lines)
+	}
+}
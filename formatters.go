@@ -0,0 +1,124 @@
+package gologger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyFormatter formats the value of a field by its key, e.g. to truncate payloads or anonymise addresses
+type KeyFormatter func(key string, value any) any
+
+// keyFormatter is a formatter registered for a key pattern
+type keyFormatter struct {
+	pattern   string
+	literals  int // number of non-wildcard characters, more specific patterns win
+	formatter KeyFormatter
+}
+
+// RegisterKeyFormatter registers a formatter for field keys on the default logger, see Logger.RegisterKeyFormatter
+func RegisterKeyFormatter(pattern string, formatter KeyFormatter) {
+	defaultLogger.RegisterKeyFormatter(pattern, formatter)
+}
+
+// RegisterKeyFormatter registers a formatter for all fields whose key matches the pattern.
+// The pattern is matched against the dotted key including groups and each of its suffixes, so "ip" matches "client.ip",
+// and * matches any characters, e.g. "http.*" or "*_ms". Exact patterns win over wildcards, more specific wildcards
+// over less specific ones and earlier registrations over later ones. Key formatters take precedence over stringers:
+// they receive the value with slog.LogValuer resolved, and their result is logged as is.
+func (l *Logger) RegisterKeyFormatter(pattern string, formatter KeyFormatter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stringers = l.stringers.withKeyFormatter(pattern, formatter)
+}
+
+// WithKeyFormatter registers a formatter for field keys on the logger, see RegisterKeyFormatter
+func WithKeyFormatter(pattern string, formatter KeyFormatter) Option {
+	return func(l *Logger) { l.stringers = l.stringers.withKeyFormatter(pattern, formatter) }
+}
+
+// withKeyFormatter returns a copy of the registry with the formatter registered for pattern.
+// Re-registering a pattern replaces its formatter.
+func (r *stringerRegistry) withKeyFormatter(pattern string, formatter KeyFormatter) *stringerRegistry {
+	next := *r
+	next.formatters = make([]keyFormatter, 0, len(r.formatters)+1)
+	next.formatters = append(next.formatters, r.formatters...)
+	for i, entry := range next.formatters {
+		if entry.pattern == pattern {
+			next.formatters[i].formatter = formatter
+			return &next
+		}
+	}
+	literals := len(strings.ReplaceAll(pattern, "*", ""))
+	next.formatters = append(next.formatters, keyFormatter{pattern: pattern, literals: literals, formatter: formatter})
+	return &next
+}
+
+// formatter returns the most specific key formatter matching the dotted key
+func (r *stringerRegistry) formatter(key string) (keyFormatter, bool) {
+	if key == "" || len(r.formatters) == 0 {
+		return keyFormatter{}, false
+	}
+
+	var best keyFormatter
+	found := false
+	for _, entry := range r.formatters {
+		if !matchDottedKey(entry.pattern, key) {
+			continue
+		}
+		exact := !strings.Contains(entry.pattern, "*")
+		bestExact := found && !strings.Contains(best.pattern, "*")
+		switch {
+		case !found,
+			exact && !bestExact,
+			exact == bestExact && entry.literals > best.literals:
+			best, found = entry, true
+		}
+	}
+	return best, found
+}
+
+// matchDottedKey reports whether the pattern matches the dotted key or one of its suffixes starting at a group boundary
+func matchDottedKey(pattern, key string) bool {
+	for {
+		if matchKey(pattern, key) {
+			return true
+		}
+		i := strings.IndexByte(key, '.')
+		if i < 0 {
+			return false
+		}
+		key = key[i+1:]
+	}
+}
+
+// matchKey reports whether key matches the pattern, where * matches any sequence of characters
+func matchKey(pattern, key string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == key
+	}
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+	key = key[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(key, part)
+		if i < 0 {
+			return false
+		}
+		key = key[i+len(part):]
+	}
+	return len(key) >= len(last) && strings.HasSuffix(key, last)
+}
+
+// applyFormatter calls a key formatter, a panic is reported and the value is returned unformatted
+func (l *Logger) applyFormatter(f keyFormatter, key string, value any) (formatted any) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.reportError(newPanicError(fmt.Sprintf("key formatter for %s", f.pattern), r))
+			formatted = value
+		}
+	}()
+	return f.formatter(key, value)
+}
@@ -0,0 +1,66 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyFormatters(t *testing.T) {
+	l := NewLogger(WithKeyFormatter("*_ms", func(_ string, v any) any {
+		if d, ok := v.(time.Duration); ok {
+			return d.Milliseconds()
+		}
+		return v
+	}))
+	l.RegisterKeyFormatter("ip", func(_ string, v any) any {
+		ip := fmt.Sprint(v)
+		return ip[:strings.LastIndexByte(ip, '.')] + ".0/24"
+	})
+	l.RegisterKeyFormatter("payload", func(_ string, v any) any { return fmt.Sprint(v)[:4] })
+	l.RegisterKeyFormatter("*tus", func(_ string, v any) any { return "less specific" })
+	l.RegisterKeyFormatter("http.*", func(key string, v any) any { return key + " formatted" })
+	RegisterStringerFor(l, func(d time.Duration) string { return "stringer" })
+
+	var fields []any
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { fields = r.Fields })
+
+	l.WithGroup("client").Info("test",
+		"ip", "192.168.1.42",
+		"duration_ms", 1500*time.Millisecond,
+		"timeout", time.Second,
+		"payload", Lazy(func() any { return "abcdefgh" }),
+		slog.Group("http", "status", 200),
+	)
+
+	expected := " client.ip=192.168.1.0/24 client.duration_ms=1500 client.timeout=stringer client.payload=abcd client.http.status=client.http.status formatted"
+	if text := formatFields(fields); text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		cases := []struct {
+			pattern, key string
+			match        bool
+		}{
+			{"ip", "ip", true},
+			{"ip", "zip", false},
+			{"ip", "client.ip", true},
+			{"client", "client.ip", false},
+			{"*_ms", "duration_ms", true},
+			{"*_ms", "duration_s", false},
+			{"http.*", "http.status", true},
+			{"*", "", true},
+			{"a*b*c", "a-b-b-c", true},
+			{"a*b*c", "acb", false},
+			{"ab*ba", "aba", false},
+		}
+		for _, c := range cases {
+			if got := matchDottedKey(c.pattern, c.key); got != c.match {
+				t.Errorf("matchDottedKey(%q, %q) = %v, expected %v", c.pattern, c.key, got, c.match)
+			}
+		}
+	})
+}
@@ -1,3 +1,5 @@
 module github.com/FrauElster/gologger/v2
 
 go 1.21.7
+
+require gopkg.in/yaml.v3 v3.0.1
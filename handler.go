@@ -0,0 +1,156 @@
+package gologger
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"os"
+)
+
+// slogHandler is a slog.Handler that routes records through a Logger, so they reach every registered callback
+type slogHandler struct {
+	l *Logger
+}
+
+// stderrLogger writes internal errors, and console output if the application has no slog handler, at every level.
+// It deliberately does not use slog's default logger, which may be backed by gologger, directly or wrapped in other
+// handlers, and would loop back into it.
+var stderrLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.Level(math.MinInt)}))
+
+// builtinHandler is the handler of slog's default logger before the application sets its own
+var builtinHandler = slog.Default().Handler()
+
+// consoleKey marks the context of records written by a console sink
+type consoleKey struct{}
+
+// Handler returns a slog.Handler backed by the default logger.
+// Use it with slog.SetDefault(slog.New(gologger.Handler())) to fan plain slog calls out to every configured sink.
+func Handler() slog.Handler { return defaultLogger.Handler() }
+
+// Handler returns a slog.Handler backed by the logger
+func (l *Logger) Handler() slog.Handler { return &slogHandler{l: l} }
+
+// Enabled reports whether the logger accepts records at the given level
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.l.Enabled(ctx, level)
+}
+
+// Handle converts the record's attributes into key-value pairs and logs it with the record's time
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil && ctx.Value(consoleKey{}) != nil {
+		// a console sink writes to a handler wrapping this one, write to stderr instead of looping
+		return stderrLogger.Handler().Handle(ctx, r)
+	}
+	args := make([]any, 0, 2*r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		args = appendAttr(args, a)
+		return true
+	})
+
+	h.l.logPC(ctx, r.Time, r.Level, r.PC, r.Message, args...)
+	return nil
+}
+
+// WithAttrs returns a handler whose records include the given attributes
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	args := make([]any, 0, 2*len(attrs))
+	for _, a := range attrs {
+		args = appendAttr(args, a)
+	}
+	return &slogHandler{l: h.l.With(args...)}
+}
+
+// WithGroup returns a handler that qualifies all following attributes with the group name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{l: h.l.WithGroup(name)}
+}
+
+// appendAttr appends an attribute as key-value pair, groups are kept as slog.GroupValue.
+// slog.LogValuer values are resolved later, once the record is known to be logged.
+func appendAttr(args []any, a slog.Attr) []any {
+	if a.Value.Kind() == slog.KindLogValuer {
+		if a.Key == "" {
+			return args
+		}
+		return append(args, a.Key, a.Value)
+	}
+	if a.Equal(slog.Attr{}) {
+		return args
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return args
+		}
+		// groups without a key are inlined, as documented for slog.Handler
+		if a.Key == "" {
+			for _, ga := range group {
+				args = appendAttr(args, ga)
+			}
+			return args
+		}
+		return append(args, a.Key, a.Value)
+	}
+
+	if a.Key == "" {
+		return args
+	}
+	return append(args, a.Key, a.Value.Any())
+}
+
+// fallbackLogger returns the logger for console output and internal errors, which must not loop back into gologger
+func fallbackLogger() *slog.Logger { return stderrLogger }
+
+// ConsoleSink writes records to the application's slog handler, it is set up by Setup and by console sinks of Configure
+type ConsoleSink struct {
+	registration
+	handler slog.Handler
+}
+
+// useConsole registers a console sink for records at or above minLevel
+func (l *Logger) useConsole(minLevel slog.Level) *ConsoleSink {
+	sink := &ConsoleSink{handler: consoleHandler()}
+	sink.remove = l.RegisterSink("console", minLevel, sink)
+	return sink
+}
+
+// consoleHandler returns the handler of slog's default logger if the application set one that is not gologger's,
+// and a text handler on stderr otherwise
+func consoleHandler() slog.Handler {
+	h := slog.Default().Handler()
+	if _, ok := h.(*slogHandler); ok || h == builtinHandler {
+		return stderrLogger.Handler()
+	}
+	return h
+}
+
+// Handle writes a record to the console handler
+func (s *ConsoleSink) Handle(r Record) {
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = context.WithValue(ctx, consoleKey{}, true)
+	if !s.handler.Enabled(ctx, r.Level) {
+		return
+	}
+
+	sr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	sr.Add(r.Fields...)
+	if r.Source != nil {
+		sr.Add("caller", formatSource(r.Source))
+	}
+	if err := s.handler.Handle(ctx, sr); err != nil {
+		fallbackLogger().Error("Failed to write to console", "error", err)
+	}
+}
+
+// Flush is a no-op, every record is written synchronously
+func (s *ConsoleSink) Flush(_ context.Context) error { return nil }
+
+// Close removes the sink from its logger
+func (s *ConsoleSink) Close(_ context.Context) error {
+	s.unregister()
+	return nil
+}
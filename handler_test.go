@@ -0,0 +1,181 @@
+package gologger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	l := NewLogger()
+
+	var captured []any
+	l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { captured = args })
+
+	logger := slog.New(l.Handler())
+
+	t.Run("level filtering", func(t *testing.T) {
+		captured = nil
+		logger.Debug("test", "key", "value")
+		if captured != nil {
+			t.Errorf("expected debug record to be filtered, got %v", captured)
+		}
+		if logger.Enabled(context.Background(), slog.LevelDebug) {
+			t.Errorf("expected debug level to be disabled")
+		}
+	})
+
+	t.Run("attrs and groups", func(t *testing.T) {
+		captured = nil
+		logger.With("service", "api").WithGroup("http").With("method", "GET").Info("test", "status", 200, slog.Group("req", "id", "abc"))
+
+		if len(captured) != 4 {
+			t.Fatalf("expected 4 arguments (two key-value pairs), got %v", captured)
+		}
+		if captured[0] != "service" || captured[1] != "api" {
+			t.Errorf("expected service=api, got %v=%v", captured[0], captured[1])
+		}
+		if fields := formatFields(captured); fields != " service=api http.method=GET http.status=200 http.req.id=abc" {
+			t.Errorf("unexpected fields %q", fields)
+		}
+	})
+
+	t.Run("record time", func(t *testing.T) {
+		var records []Record
+		l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { records = append(records, r) })
+
+		at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		r := slog.NewRecord(at, slog.LevelInfo, "queued", 0)
+		if err := l.Handler().Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Handler().Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "untimed", 0)); err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 2 || !records[0].Time.Equal(at) || records[1].Time.IsZero() {
+			t.Errorf("expected the slog record's time or the current time, got %v", records)
+		}
+	})
+
+	t.Run("no loop through console callbacks", func(t *testing.T) {
+		previous := slog.Default()
+		defer slog.SetDefault(previous)
+
+		consoleLogger := NewLogger()
+		if err := consoleLogger.Setup("info"); err != nil {
+			t.Fatal(err)
+		}
+		slog.SetDefault(slog.New(consoleLogger.Handler()))
+		slog.Info("must not recurse", "key", "value")
+
+		// handlers wrapping gologger's handler must not loop either
+		slog.SetDefault(slog.New(wrappedHandler{consoleLogger.Handler()}))
+		slog.Info("must not recurse through wrappers", "key", "value")
+	})
+}
+
+// wrappedHandler is a middleware handler around another handler
+type wrappedHandler struct{ slog.Handler }
+
+type user struct {
+	id       int
+	name     string
+	resolved *int
+}
+
+func (u user) LogValue() slog.Value {
+	*u.resolved++
+	return slog.GroupValue(slog.Int("id", u.id), slog.String("name", u.name))
+}
+
+func TestSlogValues(t *testing.T) {
+	l := NewLogger()
+
+	var fields []any
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { fields = r.Fields })
+
+	resolved := 0
+	u := user{id: 1, name: "alice", resolved: &resolved}
+	l.Debug("disabled", "user", u)
+	if resolved != 0 {
+		t.Errorf("expected LogValuer not to be resolved for disabled records, got %d resolutions", resolved)
+	}
+
+	l.Info("test",
+		"user", u,
+		slog.String("attr", "value"),
+		slog.Group("request", slog.String("method", "GET"), slog.Group("headers", "accept", "*/*")),
+		"key", "value",
+	)
+	if resolved != 1 {
+		t.Errorf("expected LogValuer to be resolved once, got %d resolutions", resolved)
+	}
+
+	data, err := json.Marshal(fieldsToMap(fields))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON := `{"attr":"value","key":"value","request":{"headers":{"accept":"*/*"},"method":"GET"},"user":{"id":1,"name":"alice"}}`
+	if string(data) != expectedJSON {
+		t.Errorf("expected nested JSON %s, got %s", expectedJSON, data)
+	}
+
+	expectedText := ` user.id=1 user.name=alice attr=value request.method=GET request.headers.accept=*/* key=value`
+	if text := formatFields(fields); text != expectedText {
+		t.Errorf("expected dotted text %q, got %q", expectedText, text)
+	}
+}
+
+func TestConsoleSink(t *testing.T) {
+	l := NewLogger()
+	if err := l.Setup("warn"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Configure(Config{Level: "warn", Sinks: []SinkConfig{{Type: "console", MinLevel: "error"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []SinkState{{Name: "console", MinLevel: "debug"}, {Name: "console", MinLevel: "error"}}
+	if sinks := l.LevelState().Sinks; !reflect.DeepEqual(sinks, expected) {
+		t.Errorf("expected console sinks %v, got %v", expected, sinks)
+	}
+	if err := l.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if sinks := l.LevelState().Sinks; len(sinks) != 0 {
+		t.Errorf("expected shutdown to remove the console sinks, got %v", sinks)
+	}
+
+	t.Run("application handler", func(t *testing.T) {
+		previous := slog.Default()
+		defer slog.SetDefault(previous)
+
+		var buf bytes.Buffer
+		slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+		l := NewLogger()
+		if err := l.Setup("info"); err != nil {
+			t.Fatal(err)
+		}
+		l.Info("to the app", "key", "value")
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("expected JSON of the application's handler, got %q: %v", buf.String(), err)
+		}
+		if entry["msg"] != "to the app" || entry["key"] != "value" {
+			t.Errorf("unexpected console entry %v", entry)
+		}
+
+		// a handler wrapping gologger's handler must not loop back into it
+		looping := NewLogger()
+		slog.SetDefault(slog.New(wrappedHandler{looping.Handler()}))
+		if err := looping.Setup("info"); err != nil {
+			t.Fatal(err)
+		}
+		looping.Info("must not recurse")
+	})
+}
@@ -0,0 +1,109 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// lazyValue is a field value computed on demand
+type lazyValue func() any
+
+// Lazy returns a field value that is computed by fn only if the record is logged, i.e. after the level, sink,
+// sampling, processor and deduplication checks passed. Processors see the value unresolved as slog.LogValuer,
+// stringers, key formatters, redaction and sinks see the computed value.
+func Lazy(fn func() any) slog.LogValuer { return lazyValue(fn) }
+
+// LogValue computes the value
+func (v lazyValue) LogValue() slog.Value { return slog.AnyValue(v()) }
+
+// lazyOf returns the Lazy value v holds, directly or as slog.Value
+func lazyOf(v any) (lazyValue, bool) {
+	switch v := v.(type) {
+	case lazyValue:
+		return v, true
+	case slog.Value:
+		if v.Kind() == slog.KindLogValuer {
+			lv, ok := v.LogValuer().(lazyValue)
+			return lv, ok
+		}
+	}
+	return nil, false
+}
+
+// resolveLazy computes the Lazy values of the record, including those in groups, converts them with the stringers
+// and masks them with the redactor. A record without Lazy values is returned unchanged.
+func (l *Logger) resolveLazy(r Record, redactor *redactor) Record {
+	var fields []any
+	var stringers *stringerRegistry
+	for i := 1; i < len(r.Fields); i += 2 {
+		if stringers == nil && containsLazy(r.Fields[i]) {
+			stringers = l.loadStringers()
+		}
+		if stringers == nil {
+			continue
+		}
+		resolved, ok := l.resolveLazyValue(stringers, redactor, fmt.Sprint(r.Fields[i-1]), r.Fields[i])
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			// records are shared, so the fields are copied instead of modified in place
+			fields = append([]any(nil), r.Fields...)
+		}
+		fields[i] = resolved
+	}
+	if fields != nil {
+		r.Fields = fields
+	}
+	return r
+}
+
+// containsLazy reports whether v is a Lazy value or a group containing one
+func containsLazy(v any) bool {
+	if _, ok := lazyOf(v); ok {
+		return true
+	}
+	sv, ok := v.(slog.Value)
+	if !ok || sv.Kind() != slog.KindGroup {
+		return false
+	}
+	for _, a := range sv.Group() {
+		if containsLazy(a.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveLazyValue resolves v if it is a Lazy value or a group containing one, it reports whether v changed
+func (l *Logger) resolveLazyValue(stringers *stringerRegistry, redactor *redactor, key string, v any) (any, bool) {
+	if lv, ok := lazyOf(v); ok {
+		// Resolve recovers a panicking fn
+		resolved := l.convertSlogValue(stringers, key, slog.AnyValue(lv).Resolve())
+		if redactor != nil {
+			resolved = redactor.redactField(key, resolved)
+		}
+		return resolved, true
+	}
+
+	sv, ok := v.(slog.Value)
+	if !ok || sv.Kind() != slog.KindGroup {
+		return v, false
+	}
+	attrs := sv.Group()
+	var resolvedAttrs []slog.Attr
+	for i, a := range attrs {
+		resolved, ok := l.resolveLazyValue(stringers, redactor, key+"."+a.Key, a.Value)
+		if !ok {
+			continue
+		}
+		if resolvedAttrs == nil {
+			resolvedAttrs = append([]slog.Attr(nil), attrs...)
+		}
+		resolvedAttrs[i] = slog.Any(a.Key, resolved)
+	}
+	if resolvedAttrs == nil {
+		return v, false
+	}
+	return slog.GroupValue(resolvedAttrs...), true
+}
@@ -0,0 +1,81 @@
+package gologger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLazy(t *testing.T) {
+	l := NewLogger(WithLevel(slog.LevelDebug), WithSampling(SamplingConfig{Interval: time.Hour, First: 1}))
+
+	calls := 0
+	payload := Lazy(func() any {
+		calls++
+		return "expensive"
+	})
+
+	if l.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("expected logger without callbacks to be disabled")
+	}
+	l.Info("no callbacks", "payload", payload)
+
+	var fields map[string]any
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { fields = fieldsToMap(r.Fields) })
+	if l.Enabled(context.Background(), slog.LevelDebug) || !l.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("expected only levels with callbacks to be enabled")
+	}
+
+	l.Debug("below every callback", "payload", payload)
+	l.Info("sampled", "payload", payload)
+	l.Info("sampled", "payload", payload)
+	if calls != 1 {
+		t.Errorf("expected a single evaluation, got %d", calls)
+	}
+	if fields["payload"] != "expensive" {
+		t.Errorf("expected resolved value, got %v", fields["payload"])
+	}
+
+	t.Run("processors and dedup", func(t *testing.T) {
+		l := NewLogger(WithDedup(DedupConfig{Window: time.Hour}), WithRedaction(RedactionConfig{}))
+		l.AddProcessor(func(r Record) (Record, bool) { return r, r.Message != "dropped" })
+		var records []Record
+		l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { records = append(records, r) })
+
+		calls := 0
+		payload := Lazy(func() any {
+			calls++
+			return "mail bob@example.com"
+		})
+		l.Info("dropped", "payload", payload)
+		for i := 0; i < 5; i++ {
+			l.Info("repeated", "payload", payload, slog.Group("request", slog.Any("body", payload)))
+		}
+		if calls != 2 {
+			t.Errorf("expected only the two values of the first repeated record to be evaluated, got %d evaluations", calls)
+		}
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 2 {
+			t.Fatalf("expected first record and summary, got %d records", len(records))
+		}
+		for _, r := range records {
+			fields := fieldsToMap(r.Fields)
+			request, _ := fields["request"].(map[string]any)
+			if fields["payload"] != "mail "+Redacted || request["body"] != "mail "+Redacted {
+				t.Errorf("expected resolved and redacted values, got %v", fields)
+			}
+		}
+		if calls != 2 {
+			t.Errorf("expected the summary to reuse the resolved values, got %d evaluations", calls)
+		}
+	})
+
+	SetLevel(slog.LevelInfo)
+	if Enabled(slog.LevelDebug) {
+		t.Error("expected debug to be disabled on the default logger")
+	}
+}
@@ -0,0 +1,226 @@
+package gologger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// LevelState is the document served by LevelHandler
+type LevelState struct {
+	Level    string            `json:"level"`               // level of the logger and of named loggers without a module level
+	Modules  map[string]string `json:"modules"`             // levels of named loggers by module
+	Sinks    []SinkState       `json:"sinks"`               // registered sinks in registration order
+	RevertAt *time.Time        `json:"revert_at,omitempty"` // time a temporary change is reverted
+}
+
+// SinkState describes a registered sink
+type SinkState struct {
+	Name     string `json:"name"`
+	MinLevel string `json:"min_level"`
+}
+
+// LevelChange is the document accepted by LevelHandler
+type LevelChange struct {
+	Level       *string            `json:"level,omitempty"`        // new level of the logger
+	Modules     map[string]*string `json:"modules,omitempty"`      // new module levels, null removes a module level
+	RevertAfter string             `json:"revert_after,omitempty"` // duration after which the change is reverted, e.g. "15m"
+}
+
+// levelRevert is a pending revert of a temporary level change
+type levelRevert struct {
+	timer   *time.Timer
+	at      time.Time
+	level   slog.Level
+	modules map[string]slog.Level
+}
+
+// LevelHandler returns an http.Handler for the levels of the default logger, see Logger.LevelHandler
+func LevelHandler() http.Handler { return defaultLogger.LevelHandler() }
+
+// LevelHandler returns an http.Handler to view and change the levels of the logger at runtime.
+// GET returns the LevelState as JSON. PUT and POST apply a LevelChange, given either as JSON body or as the query
+// parameters level, modules (a spec like "db=debug,http=warn", see SetModuleLevels) and revert_after, and return the
+// new state. With revert_after, all levels are restored to the state before the first temporary change once the
+// duration elapsed; a change without it is permanent and cancels a pending revert.
+// The handler performs no authentication, mount it behind your admin middleware.
+func (l *Logger) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut, http.MethodPost:
+			change, err := readLevelChange(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := l.ApplyLevelChange(change); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+			http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(l.LevelState()); err != nil {
+			l.reportError(fmt.Errorf("failed to write level state: %w", err))
+		}
+	})
+}
+
+// readLevelChange reads a LevelChange from the JSON body or, if there is none, from the query parameters
+func readLevelChange(r *http.Request) (LevelChange, error) {
+	var change LevelChange
+	if r.ContentLength != 0 && r.Body != nil {
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&change); err != nil {
+			return change, fmt.Errorf("invalid level change: %w", err)
+		}
+		return change, nil
+	}
+
+	query := r.URL.Query()
+	if query.Has("level") {
+		level := query.Get("level")
+		change.Level = &level
+	}
+	if query.Has("modules") {
+		modules, _, err := ParseModuleLevels(query.Get("modules"))
+		if err != nil {
+			return change, fmt.Errorf("invalid modules: %w", err)
+		}
+		change.Modules = make(map[string]*string, len(modules))
+		for module, level := range modules {
+			name := levelToString(level)
+			change.Modules[module] = &name
+		}
+	}
+	change.RevertAfter = query.Get("revert_after")
+	return change, nil
+}
+
+// GetLevelState returns the levels of the default logger, see Logger.LevelState
+func GetLevelState() LevelState { return defaultLogger.LevelState() }
+
+// LevelState returns the current levels of the logger and the registered sinks
+func (l *Logger) LevelState() LevelState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	state := LevelState{
+		Level:   levelToString(l.level),
+		Modules: make(map[string]string, len(l.modules)),
+		Sinks:   make([]SinkState, 0, len(l.sinks)),
+	}
+	for module, level := range l.modules {
+		state.Modules[module] = levelToString(level)
+	}
+	for _, s := range l.sinks {
+		state.Sinks = append(state.Sinks, SinkState{Name: s.name, MinLevel: levelToString(s.minLevel)})
+	}
+	if l.revert != nil {
+		at := l.revert.at
+		state.RevertAt = &at
+	}
+	return state
+}
+
+// ApplyLevelChange changes the levels of the default logger, see Logger.ApplyLevelChange
+func ApplyLevelChange(change LevelChange) error { return defaultLogger.ApplyLevelChange(change) }
+
+// ApplyLevelChange changes the levels of the logger as described for LevelHandler.
+// Nothing is changed if the change is invalid, the error names the offending field.
+func (l *Logger) ApplyLevelChange(change LevelChange) error {
+	var level *slog.Level
+	if change.Level != nil {
+		parsed, err := ParseLevel(*change.Level)
+		if err != nil {
+			return fmt.Errorf("invalid level: %w", err)
+		}
+		level = &parsed
+	}
+
+	modules := make(map[string]*slog.Level, len(change.Modules))
+	names := make([]string, 0, len(change.Modules))
+	for module := range change.Modules {
+		names = append(names, module)
+	}
+	sort.Strings(names) // report the same error for the same change
+	for _, module := range names {
+		if module == "" {
+			return fmt.Errorf("invalid modules: empty module name")
+		}
+		if change.Modules[module] == nil {
+			modules[module] = nil
+			continue
+		}
+		parsed, err := ParseLevel(*change.Modules[module])
+		if err != nil {
+			return fmt.Errorf("invalid level of module %q: %w", module, err)
+		}
+		modules[module] = &parsed
+	}
+
+	var revertAfter time.Duration
+	if change.RevertAfter != "" {
+		d, err := time.ParseDuration(change.RevertAfter)
+		if err != nil {
+			return fmt.Errorf("invalid revert_after: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid revert_after: %s is not positive", change.RevertAfter)
+		}
+		revertAfter = d
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// a revert restores the levels before the first temporary change
+	saved := &levelRevert{level: l.level, modules: copyModules(l.modules)}
+	if l.revert != nil {
+		l.revert.timer.Stop()
+		saved.level, saved.modules = l.revert.level, l.revert.modules
+		l.revert = nil
+	}
+	if revertAfter > 0 {
+		saved.at = time.Now().Add(revertAfter)
+		saved.timer = time.AfterFunc(revertAfter, func() { l.revertLevels(saved) })
+		l.revert = saved
+	}
+
+	if level != nil {
+		l.level = *level
+	}
+	if len(modules) > 0 {
+		next := copyModules(l.modules)
+		for module, level := range modules {
+			if level == nil {
+				delete(next, module)
+			} else {
+				next[module] = *level
+			}
+		}
+		l.modules = next
+	}
+	return nil
+}
+
+// revertLevels restores the levels saved by a temporary change unless the revert was cancelled or replaced
+func (l *Logger) revertLevels(r *levelRevert) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.revert != r {
+		return
+	}
+	l.level = r.level
+	l.modules = r.modules
+	l.revert = nil
+}
@@ -0,0 +1,103 @@
+package gologger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelHandler(t *testing.T) {
+	l := NewLogger()
+	l.RegisterSink("audit", slog.LevelWarn, &testSink{name: "audit"})
+	l.SetModuleLevel("db", slog.LevelDebug)
+	handler := l.LevelHandler()
+
+	request := func(method, target, body string) (*httptest.ResponseRecorder, LevelState) {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var state LevelState
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&state); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return rec, state
+	}
+
+	_, state := request(http.MethodGet, "/", "")
+	if state.Level != "info" || state.Modules["db"] != "debug" || state.RevertAt != nil {
+		t.Errorf("unexpected state %+v", state)
+	}
+	if len(state.Sinks) != 1 || state.Sinks[0] != (SinkState{Name: "audit", MinLevel: "warn"}) {
+		t.Errorf("unexpected sinks %+v", state.Sinks)
+	}
+
+	// JSON body, null removes a module level
+	_, state = request(http.MethodPut, "/", `{"level":"warn","modules":{"db":null,"http":"error"}}`)
+	if state.Level != "warn" || len(state.Modules) != 1 || state.Modules["http"] != "error" {
+		t.Errorf("unexpected state after PUT %+v", state)
+	}
+
+	// query parameters
+	_, state = request(http.MethodPost, "/?level=debug&modules=db.pool=trace", "")
+	if state.Level != "debug" || state.Modules["db.pool"] != "trace" || state.Modules["http"] != "error" {
+		t.Errorf("unexpected state after POST %+v", state)
+	}
+
+	// invalid changes name the field and change nothing
+	for _, body := range []string{`{"level":"loud"}`, `{"modules":{"db":"loud"}}`, `{"revert_after":"soon"}`, `{"lvl":"info"}`} {
+		rec, _ := request(http.MethodPut, "/", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected bad request for %s, got %d", body, rec.Code)
+		}
+	}
+	if rec, _ := request(http.MethodPut, "/", `{"modules":{"db":"loud"}}`); !strings.Contains(rec.Body.String(), `module "db"`) {
+		t.Errorf("expected error naming the module, got %s", rec.Body.String())
+	}
+	if l.GetLevel() != slog.LevelDebug {
+		t.Errorf("expected invalid changes to be ignored, got %v", l.GetLevel())
+	}
+
+	rec, _ := request(http.MethodDelete, "/", "")
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") == "" {
+		t.Errorf("expected method not allowed, got %d", rec.Code)
+	}
+
+	t.Run("revert", func(t *testing.T) {
+		_, state := request(http.MethodPut, "/", `{"level":"trace","revert_after":"50ms"}`)
+		if state.Level != "trace" || state.RevertAt == nil {
+			t.Fatalf("unexpected state %+v", state)
+		}
+		// a second temporary change keeps the original levels to revert to
+		_, state = request(http.MethodPut, "/?modules=db=error&revert_after=50ms", "")
+		if state.Modules["db"] != "error" {
+			t.Fatalf("unexpected state %+v", state)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for l.LevelState().RevertAt != nil && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		state = l.LevelState()
+		if state.Level != "debug" || state.RevertAt != nil {
+			t.Errorf("expected level to be reverted, got %+v", state)
+		}
+		if _, ok := state.Modules["db"]; ok || state.Modules["db.pool"] != "trace" {
+			t.Errorf("expected modules to be reverted, got %+v", state.Modules)
+		}
+
+		// a permanent change cancels a pending revert
+		request(http.MethodPut, "/", `{"level":"error","revert_after":"20ms"}`)
+		request(http.MethodPut, "/", `{"level":"warn"}`)
+		time.Sleep(50 * time.Millisecond)
+		if state := l.LevelState(); state.Level != "warn" || state.RevertAt != nil {
+			t.Errorf("expected permanent change to cancel the revert, got %+v", state)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package gologger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Additional levels, registered by default next to the four slog levels.
+// LevelPanic and LevelFatal are used by Panic and Fatal.
+const (
+	LevelTrace  = slog.Level(-8)
+	LevelNotice = slog.Level(2)
+	LevelPanic  = slog.Level(10)
+	LevelFatal  = slog.Level(12)
+)
+
+// levelRegistry maps level names to levels and back
+type levelRegistry struct {
+	mu     sync.RWMutex
+	names  map[slog.Level]string
+	levels map[string]slog.Level
+}
+
+var registry = &levelRegistry{
+	names: map[slog.Level]string{
+		LevelTrace:      "trace",
+		slog.LevelDebug: "debug",
+		slog.LevelInfo:  "info",
+		LevelNotice:     "notice",
+		slog.LevelWarn:  "warn",
+		slog.LevelError: "error",
+		LevelPanic:      "panic",
+		LevelFatal:      "fatal",
+	},
+	levels: map[string]slog.Level{
+		"trace":  LevelTrace,
+		"debug":  slog.LevelDebug,
+		"info":   slog.LevelInfo,
+		"notice": LevelNotice,
+		"warn":   slog.LevelWarn,
+		"error":  slog.LevelError,
+		"panic":  LevelPanic,
+		"fatal":  LevelFatal,
+	},
+}
+
+// RegisterLevel adds a named level, which can then be parsed, is rendered by its name and reaches every sink whose MinLevel it satisfies
+func RegisterLevel(name string, level slog.Level) error {
+	name = strings.ToLower(name)
+	if name == "" {
+		return fmt.Errorf("level name cannot be empty")
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if existing, ok := registry.levels[name]; ok && existing != level {
+		return fmt.Errorf("level %q is already registered as %d", name, existing)
+	}
+	if existing, ok := registry.names[level]; ok && existing != name {
+		return fmt.Errorf("level %d is already registered as %q", level, existing)
+	}
+
+	registry.names[level] = name
+	registry.levels[name] = level
+	return nil
+}
+
+// Levels returns all registered levels in ascending order
+func Levels() []slog.Level {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	levels := make([]slog.Level, 0, len(registry.names))
+	for level := range registry.names {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	return levels
+}
+
+// ParseLevel converts a string to a slog.Level.
+// Besides registered names it accepts slog's notation with offsets, e.g. "info+2".
+func ParseLevel(levelStr string) (slog.Level, error) {
+	registry.mu.RLock()
+	level, ok := registry.levels[strings.ToLower(levelStr)]
+	registry.mu.RUnlock()
+	if ok {
+		return level, nil
+	}
+
+	if err := level.UnmarshalText([]byte(levelStr)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", levelStr)
+	}
+	return level, nil
+}
+
+// levelToString returns the registered name of the level, unregistered levels are rendered relative to the slog levels, e.g. "info+1"
+func levelToString(level slog.Level) string {
+	registry.mu.RLock()
+	name, ok := registry.names[level]
+	registry.mu.RUnlock()
+	if ok {
+		return name
+	}
+	return strings.ToLower(level.String())
+}
+
+// Log logs a message at the given level, which can be any registered or custom level
+func Log(level slog.Level, msg string, args ...any) {
+	defaultLogger.log(context.Background(), level, msg, args...)
+}
+
+// LogContext logs a message at the given level with the fields extracted from ctx
+func LogContext(ctx context.Context, level slog.Level, msg string, args ...any) {
+	defaultLogger.log(ctx, level, msg, args...)
+}
+
+// Log logs a message at the given level, which can be any registered or custom level
+func (l *Logger) Log(level slog.Level, msg string, args ...any) {
+	l.log(context.Background(), level, msg, args...)
+}
+
+// LogContext logs a message at the given level with the fields extracted from ctx
+func (l *Logger) LogContext(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l.log(ctx, level, msg, args...)
+}
@@ -1,59 +1,138 @@
 package gologger
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // StringConverter is a function that converts a value to its string representation
 type StringConverter func(v any) string
 
-// LogCallback is the function signature for log event subscribers
+// LogCallback is the function signature for log event subscribers, see RecordCallback for the complete record
 type LogCallback func(msg string, args ...any)
 
-// Logger represents the global logger instance
+// Logger is an independent logger instance with its own level, callbacks, stringers and sinks.
+// The package-level functions delegate to a default instance, see Default.
+// Child loggers created with With, WithGroup and Named share all of this with their parent.
 type Logger struct {
-	mu        sync.RWMutex
-	level     slog.Level
-	callbacks map[slog.Level][]LogCallback
-	stringers map[reflect.Type]StringConverter
+	*core
+	fields []field  // fields bound via With
+	groups []string // groups opened via WithGroup
+	name   string   // module name set via Named
 }
 
-var (
-	defaultLogger = &Logger{level: slog.LevelInfo, callbacks: make(map[slog.Level][]LogCallback), stringers: make(map[reflect.Type]StringConverter)}
-	levels        = []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
-)
+// core holds the state shared between a logger and its children
+type core struct {
+	mu         sync.RWMutex
+	level      slog.Level
+	callbacks  []callbackEntry
+	nextID     uint64
+	stringers  *stringerRegistry // replaced on registration, so it can be read without holding mu
+	extractors []ContextExtractor
+	validation ValidationMode
+	onError    ErrorHandler
+	async      *AsyncConfig // nil while callbacks are invoked synchronously
+	dropped    atomic.Uint64
+	labels     map[string]string
+	modules    map[string]slog.Level // levels of named loggers by module prefix
+	revert     *levelRevert          // pending revert of a temporary level change
+	processors []processorEntry
+	redactor   *redactor // nil unless redaction is enabled
+	sampler    *sampler  // nil unless sampling is enabled
+	dedup      *dedupper // nil unless deduplication is enabled
+	addSource  bool
+	errors     ErrorConfig
+	suppressed atomic.Uint64
+
+	file  *FileSink
+	loki  *LokiSink
+	sinks []sinkEntry
+}
+
+// callbackEntry is a callback registered for a range of levels, the id identifies it for removal
+type callbackEntry struct {
+	id       uint64
+	name     string // identifies the callback in internal errors
+	minLevel slog.Level
+	maxLevel slog.Level
+	cb       RecordCallback
+	queue    *asyncQueue // nil unless async dispatch is enabled
+	sampler  *sampler    // overrides the logger's sampling for this sink
+}
+
+// field is a key-value pair bound to a logger, qualified by the groups that were open when it was added
+type field struct {
+	groups []string
+	key    string
+	value  any
+}
 
-// ParseLevel converts a string to a slog.Level
-func ParseLevel(levelStr string) (slog.Level, error) {
-	switch strings.ToLower(levelStr) {
-	case "debug":
-		return slog.LevelDebug, nil
-	case "info":
-		return slog.LevelInfo, nil
-	case "warn":
-		return slog.LevelWarn, nil
-	case "error":
-		return slog.LevelError, nil
-	default:
-		return slog.LevelInfo, fmt.Errorf("unknown log level %q", levelStr)
+// Option configures a Logger created with NewLogger
+type Option func(*Logger)
+
+var defaultLogger = NewLogger()
+
+// NewLogger creates a new Logger that shares no state with the default logger or any other instance.
+// Without options it logs at slog.LevelInfo and has no callbacks registered.
+func NewLogger(opts ...Option) *Logger {
+	l := &Logger{core: &core{
+		level:     slog.LevelInfo,
+		stringers: newStringerRegistry(),
+	}}
+	for _, opt := range opts {
+		opt(l)
 	}
+	return l
+}
+
+// WithLevel sets the initial logging level of the logger
+func WithLevel(level slog.Level) Option {
+	return func(l *Logger) { l.level = level }
+}
+
+// WithCallback registers a callback for the specified level on the logger
+func WithCallback(level slog.Level, cb LogCallback) Option {
+	return func(l *Logger) { l.addCallback("", level, level, exactCallback(cb)) }
 }
 
-// RegisterStringer registers a custom string conversion function for a specific type
+// WithStringer registers a custom string conversion function for a specific type on the logger
+func WithStringer[T any](converter func(T) string) Option {
+	return func(l *Logger) { l.stringers = l.stringers.with(typeOf[T](), wrapStringer(converter)) }
+}
+
+// Default returns the logger used by the package-level functions
+func Default() *Logger { return defaultLogger }
+
+// RegisterStringer registers a custom string conversion function for a specific type.
+// Converters for interface types like error or fmt.Stringer apply to every implementing type, checked in registration order
+// after the converters of concrete types. Pointers are dereferenced and slices and maps are converted element-wise.
 func RegisterStringer[T any](converter func(T) string) {
-	defaultLogger.mu.Lock()
-	defer defaultLogger.mu.Unlock()
+	RegisterStringerFor(defaultLogger, converter)
+}
+
+// RegisterStringerFor registers a custom string conversion function for a specific type on the given logger
+func RegisterStringerFor[T any](l *Logger, converter func(T) string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stringers = l.stringers.with(typeOf[T](), wrapStringer(converter))
+}
 
-	// Create a zero value of T to get its type
-	var zero T
-	t := reflect.TypeOf(zero)
+// typeOf returns the reflect.Type of T
+func typeOf[T any]() reflect.Type {
+	// go through a pointer, reflect.TypeOf of a zero interface value is nil
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
 
-	// Store the converter wrapped to handle interface{} input
-	defaultLogger.stringers[t] = func(v any) string {
+// wrapStringer wraps a typed converter to handle interface{} input
+func wrapStringer[T any](converter func(T) string) StringConverter {
+	return func(v any) string {
 		if typed, ok := v.(T); ok {
 			return converter(typed)
 		}
@@ -62,135 +141,606 @@ func RegisterStringer[T any](converter func(T) string) {
 }
 
 // GetLevel returns the current logging level
-func GetLevel() slog.Level {
-	defaultLogger.mu.RLock()
-	defer defaultLogger.mu.RUnlock()
-	return defaultLogger.level
+func GetLevel() slog.Level { return defaultLogger.GetLevel() }
+
+// GetLevel returns the current logging level of the logger
+func (l *Logger) GetLevel() slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.level
 }
 
 // SetLevel sets the logging level
-func SetLevel(level slog.Level) {
-	defaultLogger.mu.Lock()
-	defaultLogger.level = level
-	defaultLogger.mu.Unlock()
+func SetLevel(level slog.Level) { defaultLogger.SetLevel(level) }
+
+// SetLevel sets the logging level of the logger
+func (l *Logger) SetLevel(level slog.Level) {
+	l.mu.Lock()
+	l.level = level
+	l.mu.Unlock()
 }
 
-// Setup configures the default logger with slog handlers based on the given level string
-func Setup(levelStr string) error {
+// Enabled reports whether the default logger would log a record at the given level, see Logger.Enabled
+func Enabled(level slog.Level) bool { return defaultLogger.Enabled(context.Background(), level) }
+
+// Enabled reports whether the logger would log a record at the given level, i.e. the level is enabled and
+// at least one callback or sink accepts it. Use it to guard expensive preparation of a log call.
+// Sampling is not consulted, as it counts every checked record.
+func (l *Logger) Enabled(_ context.Context, level slog.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.levelFor(l.name) > level {
+		return false
+	}
+	for _, entry := range l.callbacks {
+		if entry.minLevel <= level && level <= entry.maxLevel {
+			return true
+		}
+	}
+	return false
+}
+
+// Setup sets the level of the default logger from the given level string and adds a console sink
+func Setup(levelStr string) error { return defaultLogger.Setup(levelStr) }
+
+// Setup sets the level of the logger from the given level string and adds a console sink
+func (l *Logger) Setup(levelStr string) error {
 	logLvl, err := ParseLevel(levelStr)
 	if err != nil {
 		return err
 	}
-	SetLevel(logLvl)
+	l.SetLevel(logLvl)
 
-	// Setup default slog handlers
-	RegisterCallback(slog.LevelDebug, slog.Debug)
-	RegisterCallback(slog.LevelInfo, slog.Info)
-	RegisterCallback(slog.LevelWarn, slog.Warn)
-	RegisterCallback(slog.LevelError, slog.Error)
+	// the logger's level filters records, the console has always written debug and above
+	l.useConsole(slog.LevelDebug)
 
 	return nil
 }
 
-// convertArgsToStrings applies registered string converters to args
+// convertArgsToStrings applies registered key formatters and string converters to the values of args
 func (l *Logger) convertArgsToStrings(args ...any) []any {
+	stringers := l.loadStringers()
+
+	converted := make([]any, len(args))
+	for i := 0; i < len(args); i += 2 {
+		converted[i] = args[i]
+		if i+1 < len(args) {
+			converted[i+1] = l.convertValue(stringers, fmt.Sprint(args[i]), args[i+1])
+		}
+	}
+	return converted
+}
+
+// loadStringers returns the current stringer registry, which is never modified after being stored
+func (l *Logger) loadStringers() *stringerRegistry {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
+	return l.stringers
+}
 
-	converted := make([]any, len(args))
-	for i, arg := range args {
-		if v := reflect.ValueOf(arg); v.Kind() == reflect.Ptr && v.IsNil() {
-			converted[i] = nil
-			continue
+// convertValue applies the key formatter for the field's dotted key or the registered string converter for the
+// value's type, dereferencing pointers and converting the elements of slices, arrays and maps.
+// slog.LogValuer values are resolved unless a converter is registered for their concrete type, Lazy values are kept.
+// A panicking converter is reported and the value is passed on unconverted.
+func (l *Logger) convertValue(stringers *stringerRegistry, key string, arg any) any {
+	v := reflect.ValueOf(arg)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		arg = nil
+	}
+
+	// Lazy values are converted once they are resolved after processing and deduplication, see resolveLazy
+	if lv, ok := lazyOf(arg); ok {
+		return lv
+	}
+	switch a := arg.(type) {
+	case slog.Value:
+		return l.convertSlogValue(stringers, key, a)
+	case slog.LogValuer:
+		if _, _, ok := stringers.lookupExact(v.Type()); !ok {
+			return l.convertSlogValue(stringers, key, slog.AnyValue(a))
+		}
+	}
+	if f, ok := stringers.formatter(key); ok {
+		return l.applyFormatter(f, key, arg)
+	}
+	if arg == nil || stringers.empty() {
+		return arg
+	}
+
+	if converter, t, ok := stringers.lookup(v.Type()); ok {
+		// dereference pointers to the type the converter was registered for
+		for v.Type() != t {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
 		}
+		return l.applyStringer(converter, t, v.Interface(), arg)
+	}
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
 
-		t := reflect.TypeOf(arg)
-		if converter, ok := l.stringers[t]; ok {
-			converted[i] = converter(arg)
-		} else {
-			converted[i] = arg
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !stringers.convertsElem(v.Type().Elem()) || v.Type().Elem().Kind() == reflect.Uint8 {
+			return arg
 		}
+		converted := make([]any, v.Len())
+		for i := range converted {
+			converted[i] = l.convertValue(stringers, "", v.Index(i).Interface())
+		}
+		return converted
+	case reflect.Map:
+		if !stringers.convertsElem(v.Type().Elem()) {
+			return arg
+		}
+		converted := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			converted[fmt.Sprint(iter.Key().Interface())] = l.convertValue(stringers, "", iter.Value().Interface())
+		}
+		return converted
 	}
-	return converted
+	return arg
+}
+
+// convertSlogValue resolves a slog value and converts its contents, groups stay slog.GroupValue with converted members
+func (l *Logger) convertSlogValue(stringers *stringerRegistry, key string, v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return l.convertValue(stringers, key, v.Any())
+	}
+
+	var attrs []slog.Attr
+	for _, a := range v.Group() {
+		memberKey := a.Key
+		if key != "" {
+			memberKey = key + "." + a.Key
+		}
+		converted := l.convertValue(stringers, memberKey, a.Value)
+		// groups without a key are inlined, as documented for slog.Handler
+		if group, ok := converted.(slog.Value); ok && a.Key == "" {
+			attrs = append(attrs, group.Group()...)
+			continue
+		}
+		attrs = append(attrs, slog.Any(a.Key, converted))
+	}
+	return slog.GroupValue(attrs...)
 }
 
-// log is a private helper function that handles the common logging logic
-func (l *Logger) log(level slog.Level, msg string, args ...any) {
-	// Validate args are in key-value pairs
-	if len(args)%2 != 0 {
-		panic(fmt.Sprintf("invalid number of arguments to log call: got %d, expected even number of key-value pairs", len(args)))
+// applyStringer calls a converter registered for t, a panic is reported and the original arg is returned
+func (l *Logger) applyStringer(converter StringConverter, t reflect.Type, value any, arg any) (converted any) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.reportError(newPanicError(fmt.Sprintf("stringer for %s", t), r))
+			converted = arg
+		}
+	}()
+	return converter(value)
+}
+
+// buildArgs merges the bound fields, the call's args and the fields extracted from the context
+// and applies the registered string converters.
+// Grouped fields are passed on as a single key with a slog.GroupValue.
+func (l *Logger) buildArgs(args []any, ctxArgs []any) []any {
+	if len(l.fields) == 0 && len(l.groups) == 0 {
+		if len(ctxArgs) > 0 {
+			args = append(args[:len(args):len(args)], ctxArgs...)
+		}
+		return l.convertArgsToStrings(args...)
 	}
 
-	// Validate keys are strings
+	stringers := l.loadStringers()
+	fields := make([]field, 0, len(l.fields)+len(args)/2)
+	for _, f := range l.fields {
+		fields = append(fields, field{groups: f.groups, key: f.key, value: l.convertValue(stringers, dottedKey(f.groups, f.key), f.value)})
+	}
 	for i := 0; i < len(args); i += 2 {
-		key, ok := args[i].(string)
-		if !ok {
-			panic(fmt.Sprintf("invalid key type at position %d: got %T, expected string", i, args[i]))
+		key := args[i].(string)
+		fields = append(fields, field{groups: l.groups, key: key, value: l.convertValue(stringers, dottedKey(l.groups, key), args[i+1])})
+	}
+	for i := 0; i < len(ctxArgs); i += 2 {
+		key := ctxArgs[i].(string)
+		fields = append(fields, field{key: key, value: l.convertValue(stringers, key, ctxArgs[i+1])})
+	}
+
+	return nestFields(fields, 0)
+}
+
+// dottedKey qualifies a key with its groups
+func dottedKey(groups []string, key string) string {
+	if len(groups) == 0 {
+		return key
+	}
+	return strings.Join(groups, ".") + "." + key
+}
+
+// nestFields turns fields into key-value pairs, collecting fields below the given group depth into group values
+func nestFields(fields []field, depth int) []any {
+	args := make([]any, 0, 2*len(fields))
+	done := make(map[string]bool)
+	for i, f := range fields {
+		if len(f.groups) == depth {
+			args = append(args, f.key, f.value)
+			continue
+		}
+
+		name := f.groups[depth]
+		if done[name] {
+			continue
+		}
+		done[name] = true
+
+		var members []field
+		for _, m := range fields[i:] {
+			if len(m.groups) > depth && m.groups[depth] == name {
+				members = append(members, m)
+			}
 		}
-		if key == "" {
-			panic(fmt.Sprintf("empty string key at position %d", i))
+
+		nested := nestFields(members, depth+1)
+		attrs := make([]slog.Attr, 0, len(nested)/2)
+		for j := 0; j < len(nested); j += 2 {
+			attrs = append(attrs, slog.Any(nested[j].(string), nested[j+1]))
 		}
+		args = append(args, name, slog.GroupValue(attrs...))
+	}
+	return args
+}
+
+// With returns a child of the default logger that adds the given key-value pairs to every record
+func With(args ...any) *Logger { return defaultLogger.With(args...) }
+
+// WithGroup returns a child of the default logger that qualifies all following fields with the group name
+func WithGroup(name string) *Logger { return defaultLogger.WithGroup(name) }
+
+// With returns a child logger that adds the given key-value pairs to every record.
+// The pairs are validated once, with the same rules and ValidationMode as the logging functions.
+func (l *Logger) With(args ...any) *Logger {
+	args = l.validateArgs(args)
+	if len(args) == 0 {
+		return l
+	}
+
+	fields := make([]field, len(l.fields), len(l.fields)+len(args)/2)
+	copy(fields, l.fields)
+	for i := 0; i < len(args); i += 2 {
+		fields = append(fields, field{groups: l.groups, key: args[i].(string), value: args[i+1]})
+	}
+	return &Logger{core: l.core, fields: fields, groups: l.groups, name: l.name}
+}
+
+// WithGroup returns a child logger that qualifies all following fields with the group name.
+// Groups are rendered as nested objects in JSON output and as dotted keys in text output.
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+
+	groups := make([]string, len(l.groups), len(l.groups)+1)
+	copy(groups, l.groups)
+	return &Logger{core: l.core, fields: l.fields, groups: append(groups, name), name: l.name}
+}
+
+// log is a private helper function that handles the common logging logic.
+// It must be called directly by the exported logging functions, so the caller's program counter is found.
+func (l *Logger) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	// skip log and the exported logging function
+	l.logPC(ctx, time.Time{}, level, callerPC(2), msg, args...)
+}
+
+// logPC builds a record with the given time and program counter and dispatches it to every matching callback.
+// A zero time is replaced with the current time.
+func (l *Logger) logPC(ctx context.Context, t time.Time, level slog.Level, pc uintptr, msg string, args ...any) {
+	l.emit(ctx, t, level, pc, true, msg, args...)
+}
+
+// emit builds and dispatches a record stamped with t, or the current time if t is zero.
+// Records that are sampled out are dropped unless sample is false.
+func (l *Logger) emit(ctx context.Context, t time.Time, level slog.Level, pc uintptr, sample bool, msg string, args ...any) {
+	now := t
+	if now.IsZero() {
+		now = time.Now()
 	}
+	args = l.validateArgs(args)
 
 	l.mu.RLock()
-	if l.level > level {
+	if l.levelFor(l.name) > level {
 		l.mu.RUnlock()
 		return
 	}
-	// Make a copy of callbacks to avoid holding the lock while executing them
-	callbacks := make([]LogCallback, len(l.callbacks[level]))
-	copy(callbacks, l.callbacks[level])
+	// Collect the matching callbacks and processors to avoid holding the lock while executing them
+	callbacks, overridden := l.matchingCallbacks(level)
+	processors := make([]Processor, len(l.processors))
+	for i, entry := range l.processors {
+		processors[i] = entry.p
+	}
+	redactor := l.redactor
+	sampler := l.sampler
+	dedup := l.dedup
+	addSource := l.addSource
+	errorCfg := l.errors
+	labels := l.labels
 	l.mu.RUnlock()
 
-	// convrt args with registered stringers
-	convertedArgs := l.convertArgsToStrings(args...)
+	// no callback accepts the level, skip building the record and resolving lazy values
+	if len(callbacks) == 0 {
+		return
+	}
+
+	// sample before building the record, so suppressed records are cheap
+	sampled := !sample || sampler == nil || sampler.allow(level, msg, now)
+	if !sampled && !overridden {
+		return
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var src *slog.Source
+	if addSource {
+		pc = sourcePC(pc)
+		src = source(pc)
+	}
+	record := Record{
+		Time:    now,
+		Level:   level,
+		Message: msg,
+		// merge bound and context fields, convert args with registered stringers and add error details
+		Fields:  addErrorFields(l.buildArgs(args, l.extractContext(ctx)), level, errorCfg),
+		PC:      pc,
+		Source:  src,
+		Logger:  l.name,
+		Labels:  labels,
+		Context: ctx,
+	}
+	if l.name != "" {
+		record.Fields = append([]any{LoggerKey, l.name}, record.Fields...)
+	}
+	record, ok := l.process("processor", processors, record)
+	if !ok {
+		return
+	}
+	if redactor != nil {
+		record = redactor.redact(record)
+	}
+	if dedup == nil {
+		record = l.resolveLazy(record, redactor)
+	} else if record, ok = dedup.first(record, func(r Record) Record { return l.resolveLazy(r, redactor) }); !ok {
+		return
+	}
+
+	l.dispatch(callbacks, record, sample, sampled)
+}
+
+// matchingCallbacks returns the callbacks for the level and whether any of them has its own sampling.
+// The caller must hold l.mu.
+func (l *Logger) matchingCallbacks(level slog.Level) (callbacks []callbackEntry, overridden bool) {
+	for _, entry := range l.callbacks {
+		if entry.minLevel <= level && level <= entry.maxLevel {
+			callbacks = append(callbacks, entry)
+			overridden = overridden || entry.sampler != nil
+		}
+	}
+	return callbacks, overridden
+}
+
+// dispatch passes the record to the callbacks, directly or through their queues.
+// Unless sample is false, callbacks with their own sampling decide for themselves and the others only get sampled records.
+func (l *Logger) dispatch(callbacks []callbackEntry, record Record, sample, sampled bool) {
+	level, msg, now := record.Level, record.Message, record.Time
+	for _, entry := range callbacks {
+		// sinks with their own sampling ignore the logger's sampling
+		if sample && entry.sampler != nil {
+			if !entry.sampler.allow(level, msg, now) {
+				continue
+			}
+		} else if !sampled {
+			continue
+		}
 
-	for _, cb := range callbacks {
-		cb(msg, convertedArgs...)
+		if entry.queue != nil {
+			entry.queue.enqueue(record)
+			continue
+		}
+		l.invokeCallback(entry, record)
 	}
 }
 
+// invokeCallback calls a callback and reports a panic instead of propagating it, so the remaining callbacks still run
+func (l *Logger) invokeCallback(entry callbackEntry, r Record) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			l.reportError(newPanicError(entry.name, rec))
+		}
+	}()
+	entry.cb(r)
+}
+
 // Debug logs a debug message with the given arguments
-func Debug(msg string, args ...any) { defaultLogger.log(slog.LevelDebug, msg, args...) }
+func Debug(msg string, args ...any) {
+	defaultLogger.log(context.Background(), slog.LevelDebug, msg, args...)
+}
 
 // Info logs an info message with the given arguments
-func Info(msg string, args ...any) { defaultLogger.log(slog.LevelInfo, msg, args...) }
+func Info(msg string, args ...any) {
+	defaultLogger.log(context.Background(), slog.LevelInfo, msg, args...)
+}
 
 // Warn logs a warning message with the given arguments
-func Warn(msg string, args ...any) { defaultLogger.log(slog.LevelWarn, msg, args...) }
+func Warn(msg string, args ...any) {
+	defaultLogger.log(context.Background(), slog.LevelWarn, msg, args...)
+}
 
 // Error logs an error message with the given arguments
-func Error(msg string, args ...any) { defaultLogger.log(slog.LevelError, msg, args...) }
+func Error(msg string, args ...any) {
+	defaultLogger.log(context.Background(), slog.LevelError, msg, args...)
+}
 
-// RegisterCallback registers a callback function for the specified level
-func RegisterCallback(level slog.Level, cb LogCallback) {
-	defaultLogger.mu.Lock()
-	defer defaultLogger.mu.Unlock()
-	defaultLogger.callbacks[level] = append(defaultLogger.callbacks[level], cb)
+// DebugContext logs a debug message with the given arguments and the fields extracted from ctx
+func DebugContext(ctx context.Context, msg string, args ...any) {
+	defaultLogger.log(ctx, slog.LevelDebug, msg, args...)
 }
 
-// Convenience functions for backward compatibility
-func OnDebug(cb LogCallback) {
-	RegisterCallback(slog.LevelDebug, cb)
+// InfoContext logs an info message with the given arguments and the fields extracted from ctx
+func InfoContext(ctx context.Context, msg string, args ...any) {
+	defaultLogger.log(ctx, slog.LevelInfo, msg, args...)
+}
+
+// WarnContext logs a warning message with the given arguments and the fields extracted from ctx
+func WarnContext(ctx context.Context, msg string, args ...any) {
+	defaultLogger.log(ctx, slog.LevelWarn, msg, args...)
+}
+
+// ErrorContext logs an error message with the given arguments and the fields extracted from ctx
+func ErrorContext(ctx context.Context, msg string, args ...any) {
+	defaultLogger.log(ctx, slog.LevelError, msg, args...)
 }
 
-func OnInfo(cb LogCallback) {
-	RegisterCallback(slog.LevelInfo, cb)
+// Debug logs a debug message with the given arguments
+func (l *Logger) Debug(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelDebug, msg, args...)
+}
+
+// Info logs an info message with the given arguments
+func (l *Logger) Info(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelInfo, msg, args...)
+}
+
+// Warn logs a warning message with the given arguments
+func (l *Logger) Warn(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelWarn, msg, args...)
+}
+
+// Error logs an error message with the given arguments
+func (l *Logger) Error(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelError, msg, args...)
+}
+
+// DebugContext logs a debug message with the given arguments and the fields extracted from ctx
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelDebug, msg, args...)
+}
+
+// InfoContext logs an info message with the given arguments and the fields extracted from ctx
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelInfo, msg, args...)
+}
+
+// WarnContext logs a warning message with the given arguments and the fields extracted from ctx
+func (l *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelWarn, msg, args...)
+}
+
+// ErrorContext logs an error message with the given arguments and the fields extracted from ctx
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelError, msg, args...)
+}
+
+// RegisterCallback registers a callback function for the specified level.
+// The returned function removes the callback again.
+func RegisterCallback(level slog.Level, cb LogCallback) func() {
+	return defaultLogger.RegisterCallback(level, cb)
+}
+
+// RegisterCallback registers a callback function for the specified level on the logger.
+// The returned function removes the callback again.
+func (l *Logger) RegisterCallback(level slog.Level, cb LogCallback) func() {
+	return l.registerRange("", level, level, exactCallback(cb))
+}
+
+// registerRange registers cb for all levels between minLevel and maxLevel and returns its remover.
+// The name identifies the callback in internal errors.
+func (l *Logger) registerRange(name string, minLevel, maxLevel slog.Level, cb RecordCallback) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := l.addCallback(name, minLevel, maxLevel, cb)
+
+	var once sync.Once
+	return func() { once.Do(func() { l.removeCallback(id) }) }
+}
+
+// exactCallback adapts a LogCallback to a RecordCallback
+func exactCallback(cb LogCallback) RecordCallback {
+	return func(r Record) { cb(r.Message, r.Fields...) }
 }
 
-func OnWarn(cb LogCallback) {
-	RegisterCallback(slog.LevelWarn, cb)
+// addCallback appends a callback entry and returns its id, the caller must hold l.mu.
+// Unnamed callbacks are named after their id.
+func (l *Logger) addCallback(name string, minLevel, maxLevel slog.Level, cb RecordCallback) uint64 {
+	l.nextID++
+	if name == "" {
+		name = fmt.Sprintf("callback %d", l.nextID)
+	}
+	entry := callbackEntry{id: l.nextID, name: name, minLevel: minLevel, maxLevel: maxLevel, cb: cb}
+	if l.async != nil {
+		entry.queue = l.newQueue(entry)
+	}
+	l.callbacks = append(l.callbacks, entry)
+	return l.nextID
 }
 
-func OnError(cb LogCallback) {
-	RegisterCallback(slog.LevelError, cb)
+// removeCallback removes the callback entry with the given id.
+// With async dispatch it waits until the callback processed its queued records.
+func (l *Logger) removeCallback(id uint64) {
+	l.removeCallbackContext(context.Background(), id)
 }
 
-func getLevelsAbove(level slog.Level) []slog.Level {
-	for i, l := range levels {
-		if l == level {
-			return levels[i:]
+// removeCallbackContext removes the callback entry with the given id. With async dispatch it waits until the callback
+// processed its queued records, or returns ctx.Err() if ctx ends first while the workers keep draining in the background.
+func (l *Logger) removeCallbackContext(ctx context.Context, id uint64) error {
+	var queue *asyncQueue
+
+	l.mu.Lock()
+	for i, entry := range l.callbacks {
+		if entry.id == id {
+			// copy instead of modifying in place, so the backing array is never shared with stale entries
+			remaining := make([]callbackEntry, 0, len(l.callbacks)-1)
+			remaining = append(remaining, l.callbacks[:i]...)
+			l.callbacks = append(remaining, l.callbacks[i+1:]...)
+			queue = entry.queue
+			break
 		}
 	}
+	l.mu.Unlock()
+
+	// drain outside the lock, the callbacks may need it to report errors
+	if queue != nil {
+		return queue.close(ctx)
+	}
 	return nil
 }
+
+// Convenience functions for backward compatibility
+func OnDebug(cb LogCallback) func() {
+	return RegisterCallback(slog.LevelDebug, cb)
+}
+
+func OnInfo(cb LogCallback) func() {
+	return RegisterCallback(slog.LevelInfo, cb)
+}
+
+func OnWarn(cb LogCallback) func() {
+	return RegisterCallback(slog.LevelWarn, cb)
+}
+
+func OnError(cb LogCallback) func() {
+	return RegisterCallback(slog.LevelError, cb)
+}
+
+func (l *Logger) OnDebug(cb LogCallback) func() {
+	return l.RegisterCallback(slog.LevelDebug, cb)
+}
+
+func (l *Logger) OnInfo(cb LogCallback) func() {
+	return l.RegisterCallback(slog.LevelInfo, cb)
+}
+
+func (l *Logger) OnWarn(cb LogCallback) func() {
+	return l.RegisterCallback(slog.LevelWarn, cb)
+}
+
+func (l *Logger) OnError(cb LogCallback) func() {
+	return l.RegisterCallback(slog.LevelError, cb)
+}
@@ -1,8 +1,17 @@
 package gologger
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,11 +26,7 @@ type testStruct struct {
 
 func TestArgumentValidation(t *testing.T) {
 	// Reset the default logger before each test
-	defaultLogger = &Logger{
-		level:     slog.LevelInfo,
-		callbacks: make(map[slog.Level][]LogCallback),
-		stringers: make(map[reflect.Type]StringConverter),
-	}
+	defaultLogger = NewLogger()
 
 	tests := []struct {
 		name          string
@@ -80,11 +85,7 @@ func TestArgumentValidation(t *testing.T) {
 
 func TestStringConversion(t *testing.T) {
 	// Reset the default logger before each test
-	defaultLogger = &Logger{
-		level:     slog.LevelInfo,
-		callbacks: make(map[slog.Level][]LogCallback),
-		stringers: make(map[reflect.Type]StringConverter),
-	}
+	defaultLogger = NewLogger()
 
 	t.Run("time.Time conversion", func(t *testing.T) {
 		// Register a custom time formatter
@@ -208,3 +209,388 @@ func TestStringConversion(t *testing.T) {
 		}
 	})
 }
+
+func TestLoggerInstances(t *testing.T) {
+	defaultLogger = NewLogger()
+
+	var defaultCaptured, instanceCaptured []any
+	RegisterCallback(slog.LevelDebug, func(msg string, args ...any) { defaultCaptured = args })
+
+	l := NewLogger(
+		WithLevel(slog.LevelDebug),
+		WithStringer(func(tm time.Time) string { return tm.Format("2006-01-02") }),
+		WithCallback(slog.LevelDebug, func(msg string, args ...any) { instanceCaptured = args }),
+	)
+
+	testTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.Debug("test", "timestamp", testTime)
+
+	if defaultCaptured != nil {
+		t.Errorf("expected default logger to stay silent, got %v", defaultCaptured)
+	}
+	if len(instanceCaptured) != 2 || instanceCaptured[1] != "2024-01-01" {
+		t.Errorf("expected instance stringer to be applied, got %v", instanceCaptured)
+	}
+
+	// the default logger is still at info level and has no stringers
+	SetLevel(slog.LevelDebug)
+	Debug("test", "timestamp", testTime)
+	if len(defaultCaptured) != 2 || defaultCaptured[1] != testTime {
+		t.Errorf("expected unconverted time on default logger, got %v", defaultCaptured)
+	}
+	if l.GetLevel() != slog.LevelDebug || NewLogger().GetLevel() != slog.LevelInfo {
+		t.Errorf("expected levels to be independent")
+	}
+}
+
+func TestChildLoggers(t *testing.T) {
+	defaultLogger = NewLogger()
+	RegisterStringer(func(tm time.Time) string { return tm.Format("2006-01-02") })
+
+	var captured []any
+	RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { captured = args })
+
+	testTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("bound fields", func(t *testing.T) {
+		child := With("request_id", "abc", "started", testTime)
+		child.Info("test", "user", "bob")
+
+		if len(captured) != 6 {
+			t.Fatalf("expected 6 arguments (three key-value pairs), got %v", captured)
+		}
+		if captured[0] != "request_id" || captured[1] != "abc" {
+			t.Errorf("expected request_id=abc first, got %v=%v", captured[0], captured[1])
+		}
+		if captured[3] != "2024-01-01" {
+			t.Errorf("expected bound field to be converted, got '%v'", captured[3])
+		}
+		if captured[4] != "user" || captured[5] != "bob" {
+			t.Errorf("expected user=bob last, got %v=%v", captured[4], captured[5])
+		}
+
+		// the parent is unaffected
+		Info("test")
+		if len(captured) != 0 {
+			t.Errorf("expected no arguments on parent, got %v", captured)
+		}
+	})
+
+	t.Run("groups", func(t *testing.T) {
+		WithGroup("http").With("method", "GET").WithGroup("req").Info("test", "id", 1)
+
+		if fields := formatFields(captured); fields != " http.method=GET http.req.id=1" {
+			t.Errorf("unexpected text fields %q", fields)
+		}
+
+		data, err := json.Marshal(fieldsToMap(captured))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"http":{"method":"GET","req":{"id":1}}}` {
+			t.Errorf("unexpected json fields %s", data)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected panic for odd number of arguments")
+			}
+		}()
+		With("key")
+	})
+}
+
+func TestContextExtractors(t *testing.T) {
+	type ctxKey struct{}
+	type TenantID string
+
+	defaultLogger = NewLogger()
+	RegisterStringer(func(id TenantID) string { return "tenant-" + string(id) })
+	RegisterContextExtractor(func(ctx context.Context) []any {
+		if id, ok := ctx.Value(ctxKey{}).(TenantID); ok {
+			return []any{"tenant", id}
+		}
+		return nil
+	})
+
+	var captured []any
+	RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { captured = args })
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, TenantID("42"))
+	InfoContext(ctx, "test", "key", "value")
+
+	if len(captured) != 4 {
+		t.Fatalf("expected 4 arguments (two key-value pairs), got %v", captured)
+	}
+	if captured[2] != "tenant" || captured[3] != "tenant-42" {
+		t.Errorf("expected converted tenant field, got %v=%v", captured[2], captured[3])
+	}
+
+	With("request_id", "abc").InfoContext(ctx, "test")
+	if fields := formatFields(captured); fields != " request_id=abc tenant=tenant-42" {
+		t.Errorf("unexpected fields %q", fields)
+	}
+
+	Info("test")
+	if len(captured) != 0 {
+		t.Errorf("expected no context fields without context, got %v", captured)
+	}
+}
+
+func TestUnregisterCallback(t *testing.T) {
+	l := NewLogger()
+
+	var calls int
+	remove := l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { calls++ })
+	l.Info("test")
+	remove()
+	l.Info("test")
+	remove()
+
+	if calls != 1 {
+		t.Errorf("expected 1 call before removal, got %d", calls)
+	}
+}
+
+func TestCustomLevels(t *testing.T) {
+	if err := RegisterLevel("audit", slog.Level(6)); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterLevel("audit", slog.Level(7)); err == nil {
+		t.Errorf("expected error when re-registering a name with another level")
+	}
+
+	for name, expected := range map[string]slog.Level{"trace": LevelTrace, "NOTICE": LevelNotice, "fatal": LevelFatal, "audit": 6, "info+1": 1} {
+		level, err := ParseLevel(name)
+		if err != nil || level != expected {
+			t.Errorf("ParseLevel(%q) = %v, %v, expected %v", name, level, err, expected)
+		}
+	}
+	if levelToString(LevelNotice) != "notice" || levelToString(slog.Level(3)) != "info+3" {
+		t.Errorf("unexpected level names %q, %q", levelToString(LevelNotice), levelToString(slog.Level(3)))
+	}
+
+	l := NewLogger(WithLevel(LevelTrace))
+	var captured []slog.Level
+	l.RegisterRecordCallback(LevelNotice, func(r Record) { captured = append(captured, r.Level) })
+
+	l.Log(LevelTrace, "test")
+	l.Info("test")
+	l.Log(LevelNotice, "test")
+	l.Log(slog.Level(3), "test")
+	l.Error("test")
+	l.Log(LevelFatal, "test")
+
+	expected := []slog.Level{LevelNotice, 3, slog.LevelError, LevelFatal}
+	if !reflect.DeepEqual(captured, expected) {
+		t.Errorf("expected levels %v, got %v", expected, captured)
+	}
+}
+
+func TestFatalFlushesSinks(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, _ := io.ReadAll(gz)
+		received <- string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	l := NewLogger()
+	sink, err := l.UseLoki(LokiConfig{URL: server.URL, BatchWait: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close(context.Background())
+
+	exitCode := -1
+	exitFunc = func(code int) { exitCode = code }
+	defer func() { exitFunc = os.Exit }()
+
+	l.Fatal("crash", "key", "value")
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	select {
+	case body := <-received:
+		if !strings.Contains(body, `"level":"fatal"`) || !strings.Contains(body, "crash key=value") {
+			t.Errorf("unexpected loki payload %s", body)
+		}
+	default:
+		t.Errorf("expected loki batch to be sent before exiting")
+	}
+
+	t.Run("panic", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected panic with message, got %v", r)
+			}
+			select {
+			case body := <-received:
+				if !strings.Contains(body, `"level":"panic"`) {
+					t.Errorf("unexpected loki payload %s", body)
+				}
+			default:
+				t.Errorf("expected loki batch to be sent before panicking")
+			}
+		}()
+		l.Panic("boom")
+	})
+}
+
+func TestLokiCloseDeadline(t *testing.T) {
+	release := make(chan struct{})
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		body, _ := io.ReadAll(gz)
+		received <- string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	l := NewLogger()
+	sink, err := l.UseLoki(LokiConfig{URL: server.URL, BatchWait: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("last words")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sink.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+
+	// the last batch is still sent and a second Close waits for it
+	close(release)
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case body := <-received:
+		if !strings.Contains(body, "last words") {
+			t.Errorf("unexpected loki payload %s", body)
+		}
+	default:
+		t.Error("expected the buffered batch to be sent")
+	}
+}
+
+func TestValidationModes(t *testing.T) {
+	var reported []error
+	l := NewLogger(WithValidationMode(ValidationRepair), WithErrorHandler(func(err error) { reported = append(reported, err) }))
+
+	var captured []any
+	l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { captured = args })
+
+	tests := []struct {
+		name     string
+		args     []any
+		expected []any
+	}{
+		{name: "dangling key", args: []any{"key1", "value1", "key2"}, expected: []any{"key1", "value1", BadKey, "key2"}},
+		{name: "non-string key", args: []any{123, "key", "value"}, expected: []any{BadKey, 123, "key", "value"}},
+		{name: "empty string key", args: []any{"", "value1"}, expected: []any{BadKey, "value1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reported = nil
+			l.Info("test", tt.args...)
+			if !reflect.DeepEqual(captured, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, captured)
+			}
+			if len(reported) != 1 {
+				t.Errorf("expected 1 reported error, got %v", reported)
+			}
+		})
+	}
+
+	t.Run("silent", func(t *testing.T) {
+		reported = nil
+		l.SetValidationMode(ValidationSilent)
+		l.Info("test", "key")
+		if !reflect.DeepEqual(captured, []any{BadKey, "key"}) {
+			t.Errorf("expected repaired args, got %v", captured)
+		}
+		if len(reported) != 0 {
+			t.Errorf("expected no reported errors, got %v", reported)
+		}
+	})
+}
+
+func TestPanicIsolation(t *testing.T) {
+	type Exploding struct{}
+
+	var reported []error
+	l := NewLogger(WithErrorHandler(func(err error) { reported = append(reported, err) }))
+	RegisterStringerFor(l, func(Exploding) string { panic("stringer boom") })
+
+	l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { panic("callback boom") })
+	var captured []any
+	l.RegisterCallback(slog.LevelInfo, func(msg string, args ...any) { captured = args })
+
+	l.Info("test", "value", Exploding{})
+
+	if len(captured) != 2 || captured[1] != (Exploding{}) {
+		t.Errorf("expected later callback to receive the unconverted value, got %v", captured)
+	}
+	if len(reported) != 2 {
+		t.Fatalf("expected 2 reported panics, got %v", reported)
+	}
+	for i, source := range []string{"stringer for gologger.Exploding", "callback 1"} {
+		var panicErr *PanicError
+		if !errors.As(reported[i], &panicErr) || panicErr.Source != source {
+			t.Errorf("expected panic from %q, got %v", source, reported[i])
+		}
+	}
+}
+
+func TestRecordCallback(t *testing.T) {
+	l := NewLogger(WithLabels(map[string]string{"service": "api"}))
+
+	var first, second Record
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { first = r })
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { second = r })
+
+	ctx := context.WithValue(context.Background(), testStruct{}, "value")
+	l.With("request_id", "abc").WarnContext(ctx, "test", "key", "value")
+
+	if first.Time.IsZero() || !first.Time.Equal(second.Time) {
+		t.Errorf("expected one shared timestamp, got %v and %v", first.Time, second.Time)
+	}
+	if first.Level != slog.LevelWarn || first.Message != "test" {
+		t.Errorf("unexpected level or message %v %q", first.Level, first.Message)
+	}
+	if !reflect.DeepEqual(first.Fields, []any{"request_id", "abc", "key", "value"}) {
+		t.Errorf("unexpected fields %v", first.Fields)
+	}
+	if first.Labels["service"] != "api" || first.Context.Value(testStruct{}) != "value" {
+		t.Errorf("expected labels and context to be passed on, got %v", first)
+	}
+	if caller := first.Caller(); !strings.HasSuffix(caller.Function, "TestRecordCallback") {
+		t.Errorf("expected caller to be the test function, got %q", caller.Function)
+	}
+
+	var pkgCaller runtime.Frame
+	defaultLogger = NewLogger()
+	RegisterRecordCallback(slog.LevelInfo, func(r Record) { pkgCaller = r.Caller() })
+	Info("test")
+	if !strings.HasSuffix(pkgCaller.Function, "TestRecordCallback") {
+		t.Errorf("expected caller of package-level function to be the test function, got %q", pkgCaller.Function)
+	}
+}
@@ -3,21 +3,25 @@ package gologger
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
 type LokiConfig struct {
-	URL       string            // Loki server URL
-	BatchWait time.Duration     // Maximum amount of time to wait before sending a batch
-	Labels    map[string]string // Default labels to add to all logs
-	Tenant    string            // Optional tenant ID for multi-tenancy
-	MinLevel  *slog.Level       // Minimum log level to send to Loki
+	URL        string            // Loki server URL
+	BatchWait  time.Duration     // Maximum amount of time to wait before sending a batch
+	Labels     map[string]string // Default labels to add to all logs
+	Tenant     string            // Optional tenant ID for multi-tenancy
+	MinLevel   *slog.Level       // Minimum log level to send to Loki
+	Processors []Processor       // Processors that only apply to this sink
+	Sampling   *SamplingConfig   // Sampling that replaces the logger's sampling for this sink
 }
 
 type lokiStream struct {
@@ -30,27 +34,29 @@ type lokiBatch struct {
 }
 
 type buffer struct {
-	entries []logEntry
+	entries []Record
 	mu      sync.Mutex
 }
 
-type logEntry struct {
-	timestamp time.Time
-	level     slog.Level
-	msg       string
-	args      []any
-}
-
-var (
+// LokiSink is a handle to a Loki integration set up with UseLoki
+type LokiSink struct {
+	registration
+	l         *Logger
+	cfg       LokiConfig
+	client    *http.Client
 	logBuffer *buffer
 	ticker    *time.Ticker
-	done      chan bool
-)
+	done      chan struct{}
+	stopped   chan struct{}
+}
 
 // UseLoki sets up logging callbacks that send logs to a Loki instance
-func UseLoki(cfg LokiConfig) error {
+func UseLoki(cfg LokiConfig) (*LokiSink, error) { return defaultLogger.UseLoki(cfg) }
+
+// UseLoki sets up logging callbacks on the logger that send logs to a Loki instance
+func (l *Logger) UseLoki(cfg LokiConfig) (*LokiSink, error) {
 	if cfg.URL == "" {
-		return fmt.Errorf("Loki URL cannot be empty")
+		return nil, fmt.Errorf("Loki URL cannot be empty")
 	}
 
 	// Set default values if not provided
@@ -67,133 +73,179 @@ func UseLoki(cfg LokiConfig) error {
 	}
 
 	// Initialize buffer and control channels
-	logBuffer = &buffer{
-		entries: make([]logEntry, 0),
+	sink := &LokiSink{
+		l:      l,
+		cfg:    cfg,
+		client: &http.Client{Timeout: 5 * time.Second},
+		logBuffer: &buffer{
+			entries: make([]Record, 0),
+		},
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		ticker:  time.NewTicker(cfg.BatchWait),
 	}
-	done = make(chan bool)
-	ticker = time.NewTicker(cfg.BatchWait)
+	l.mu.Lock()
+	l.loki = sink
+	l.mu.Unlock()
 
 	// Start batch processing
-	go processBatches(cfg)
+	go sink.processBatches()
 
 	minLevel := slog.LevelDebug
 	if cfg.MinLevel != nil {
 		minLevel = *cfg.MinLevel
 	}
-	levelsToRegister := getLevelsAbove(minLevel)
-	for idx := range levelsToRegister {
-		level := levelsToRegister[idx]
-		RegisterCallback(level, func(msg string, args ...any) {
-			logBuffer.mu.Lock()
-			logBuffer.entries = append(logBuffer.entries, logEntry{
-				timestamp: time.Now(),
-				level:     level,
-				msg:       msg,
-				args:      args,
-			})
-			logBuffer.mu.Unlock()
-		})
+	sink.remove = l.RegisterSink("loki "+cfg.URL, minLevel, sink, cfg.Processors...)
+	if cfg.Sampling != nil {
+		l.SetSinkSampling("loki "+cfg.URL, cfg.Sampling)
 	}
 
-	return nil
+	return sink, nil
+}
+
+// Handle buffers a record until the next batch is sent
+func (s *LokiSink) Handle(r Record) {
+	s.logBuffer.mu.Lock()
+	s.logBuffer.entries = append(s.logBuffer.entries, r)
+	s.logBuffer.mu.Unlock()
+}
+
+// Close removes the sink from its logger, sends the remaining logs and stops the batch processing.
+// It returns ctx.Err() if the context ends before the last batch is sent, the batch is still sent in the background
+// and a later Close waits for it again.
+func (s *LokiSink) Close(ctx context.Context) error {
+	if s.unregister() {
+		s.l.mu.Lock()
+		if s.l.loki == s {
+			s.l.loki = nil
+		}
+		s.l.mu.Unlock()
+
+		s.ticker.Stop()
+		close(s.done)
+	}
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush synchronously sends all buffered logs to Loki
+func (s *LokiSink) Flush(ctx context.Context) error {
+	return s.sendBatch(ctx)
 }
 
 // StopLoki gracefully shuts down the Loki integration
-func StopLoki() {
-	if ticker != nil {
-		ticker.Stop()
-		done <- true
+func StopLoki() { defaultLogger.StopLoki() }
+
+// StopLoki gracefully shuts down the Loki integration of the logger
+func (l *Logger) StopLoki() {
+	l.mu.RLock()
+	sink := l.loki
+	l.mu.RUnlock()
+
+	if sink != nil {
+		sink.Close(context.Background())
 	}
 }
 
-func processBatches(cfg LokiConfig) {
-	client := &http.Client{Timeout: 5 * time.Second}
+func (s *LokiSink) processBatches() {
+	defer close(s.stopped)
 
 	for {
 		select {
-		case <-ticker.C:
-			sendBatch(client, cfg)
-		case <-done:
+		case <-s.ticker.C:
+			if err := s.sendBatch(context.Background()); err != nil {
+				fallbackLogger().Error("Failed to send logs to Loki", "error", err)
+			}
+		case <-s.done:
 			// Send any remaining logs before shutting down
-			sendBatch(client, cfg)
+			if err := s.sendBatch(context.Background()); err != nil {
+				fallbackLogger().Error("Failed to send logs to Loki", "error", err)
+			}
 			return
 		}
 	}
 }
 
-func sendBatch(client *http.Client, cfg LokiConfig) {
-	logBuffer.mu.Lock()
-	if len(logBuffer.entries) == 0 {
-		logBuffer.mu.Unlock()
-		return
+// sendBatch sends all buffered logs to Loki
+func (s *LokiSink) sendBatch(ctx context.Context) error {
+	cfg := s.cfg
+
+	s.logBuffer.mu.Lock()
+	if len(s.logBuffer.entries) == 0 {
+		s.logBuffer.mu.Unlock()
+		return nil
 	}
 
 	// Take current entries and reset the buffer
-	entries := logBuffer.entries
-	logBuffer.entries = make([]logEntry, 0)
-	logBuffer.mu.Unlock()
+	entries := s.logBuffer.entries
+	s.logBuffer.entries = make([]Record, 0)
+	s.logBuffer.mu.Unlock()
 
-	// Group entries by level
-	streamsByLevel := make(map[slog.Level][][2]string)
+	// Group entries by level and labels
+	streams := make(map[string]*lokiStream)
+	var streamOrder []string
 	for _, entry := range entries {
 		// Format message with args
-		var fields string
-		for i := 0; i < len(entry.args); i += 2 {
-			if i+1 < len(entry.args) {
-				fields += fmt.Sprintf(" %v=%v", entry.args[i], entry.args[i+1])
-			}
+		message := fmt.Sprintf("%s%s", entry.Message, formatFields(entry.Fields))
+		if entry.Source != nil {
+			message += " caller=" + formatSource(entry.Source)
 		}
-		message := fmt.Sprintf("%s%s", entry.msg, fields)
 
 		// Create timestamp in nanosecond precision
-		timestamp := fmt.Sprintf("%d", entry.timestamp.UnixNano())
-
-		streamsByLevel[entry.level] = append(streamsByLevel[entry.level], [2]string{timestamp, message})
-	}
-
-	// Create batch payload
-	batch := lokiBatch{
-		Streams: make([]lokiStream, 0, len(streamsByLevel)),
-	}
+		timestamp := fmt.Sprintf("%d", entry.Time.UnixNano())
 
-	// Create a stream for each level
-	for level, values := range streamsByLevel {
 		labels := make(map[string]string)
-		for k, v := range cfg.Labels {
+		for k, v := range mergeLabels(cfg.Labels, entry.Labels) {
 			labels[k] = v
 		}
-		labels["level"] = levelToString(level)
+		labels["level"] = levelToString(entry.Level)
+		if entry.Logger != "" {
+			labels[LoggerKey] = entry.Logger
+		}
 
-		batch.Streams = append(batch.Streams, lokiStream{
-			Stream: labels,
-			Values: values,
-		})
+		key := streamKey(labels)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			streamOrder = append(streamOrder, key)
+		}
+		stream.Values = append(stream.Values, [2]string{timestamp, message})
+	}
+
+	// Create batch payload with a stream for each label set
+	batch := lokiBatch{
+		Streams: make([]lokiStream, 0, len(streams)),
+	}
+	for _, key := range streamOrder {
+		batch.Streams = append(batch.Streams, *streams[key])
 	}
 
 	// Send to Loki
 	payload, err := json.Marshal(batch)
 	if err != nil {
-		slog.Error("Failed to marshal Loki batch", "error", err)
-		return
+		return fmt.Errorf("failed to marshal Loki batch: %w", err)
 	}
 
 	// Compress the payload
 	var buf bytes.Buffer
 	gz := gzip.NewWriter(&buf)
 	if _, err := gz.Write(payload); err != nil {
-		slog.Error("Failed to compress payload", "error", err)
-		return
+		return fmt.Errorf("failed to compress payload: %w", err)
 	}
 	if err := gz.Close(); err != nil {
-		slog.Error("Failed to close gzip writer", "error", err)
-		return
+		return fmt.Errorf("failed to close gzip writer: %w", err)
 	}
 
 	url := strings.TrimRight(cfg.URL, "/") + "/loki/api/v1/push"
-	req, err := http.NewRequest("POST", url, &buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &buf)
 	if err != nil {
-		slog.Error("Failed to create Loki request", "error", err)
-		return
+		return fmt.Errorf("failed to create Loki request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -202,31 +254,29 @@ func sendBatch(client *http.Client, cfg LokiConfig) {
 		req.Header.Set("X-Scope-OrgID", cfg.Tenant)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		slog.Error("Failed to send logs to Loki", "error", err)
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		slog.Error("Unexpected response from Loki",
-			"statusCode", resp.StatusCode,
-			"status", resp.Status)
+		return fmt.Errorf("unexpected response from Loki: %s", resp.Status)
 	}
+	return nil
 }
 
-func levelToString(level slog.Level) string {
-	switch level {
-	case slog.LevelDebug:
-		return "debug"
-	case slog.LevelInfo:
-		return "info"
-	case slog.LevelWarn:
-		return "warn"
-	case slog.LevelError:
-		return "error"
-	default:
-		return "unknown"
+// streamKey returns a canonical representation of a label set
+func streamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%q=%q,", k, labels[k])
 	}
+	return b.String()
 }
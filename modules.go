@@ -0,0 +1,141 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// LoggerKey is the field key and Loki label of a named logger's name
+const LoggerKey = "logger"
+
+// Named returns a child of the default logger with the given name, see Logger.Named
+func Named(name string) *Logger { return defaultLogger.Named(name) }
+
+// Named returns a child logger for a module. Names are hierarchical: naming a logger that already has a name
+// appends to it with a dot, e.g. Named("db").Named("pool") is "db.pool". The logger's level is resolved from the
+// most specific module level, see SetModuleLevel, and its name is added to every record as the logger field.
+func (l *Logger) Named(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return &Logger{core: l.core, fields: l.fields, groups: l.groups, name: name}
+}
+
+// Name returns the name of the logger, which is empty unless it was created with Named
+func (l *Logger) Name() string { return l.name }
+
+// SetModuleLevel sets the level of a module of the default logger, see Logger.SetModuleLevel
+func SetModuleLevel(module string, level slog.Level) { defaultLogger.SetModuleLevel(module, level) }
+
+// SetModuleLevel sets the level of all named loggers whose name is module or starts with module followed by a dot.
+// A more specific module level wins, loggers without a matching module use the logger's level.
+func (l *Logger) SetModuleLevel(module string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.modules == nil {
+		l.modules = make(map[string]slog.Level)
+	}
+	l.modules[module] = level
+}
+
+// ResetModuleLevel removes the level of a module of the default logger
+func ResetModuleLevel(module string) { defaultLogger.ResetModuleLevel(module) }
+
+// ResetModuleLevel removes the level of a module, so its loggers use the next less specific level again
+func (l *Logger) ResetModuleLevel(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.modules, module)
+}
+
+// ModuleLevels returns the module levels of the default logger
+func ModuleLevels() map[string]slog.Level { return defaultLogger.ModuleLevels() }
+
+// ModuleLevels returns a copy of the configured module levels
+func (l *Logger) ModuleLevels() map[string]slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return copyModules(l.modules)
+}
+
+// SetModuleLevels configures the levels of the default logger from a spec, see Logger.SetModuleLevels
+func SetModuleLevels(spec string) error { return defaultLogger.SetModuleLevels(spec) }
+
+// SetModuleLevels replaces all module levels with the ones of a comma-separated spec like "db=debug,http=warn,*=info",
+// where * sets the logger's level. Nothing is changed if the spec is invalid.
+func (l *Logger) SetModuleLevels(spec string) error {
+	modules, level, err := ParseModuleLevels(spec)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.modules = modules
+	if level != nil {
+		l.level = *level
+	}
+	return nil
+}
+
+// ParseModuleLevels parses a comma-separated spec like "db=debug,http=warn,*=info" into module levels.
+// The level of * is returned separately and is nil if the spec does not contain it.
+func ParseModuleLevels(spec string) (modules map[string]slog.Level, level *slog.Level, err error) {
+	modules = make(map[string]slog.Level)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		module, levelStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid module level %q: expected module=level", part)
+		}
+		module = strings.TrimSpace(module)
+		parsed, err := ParseLevel(strings.TrimSpace(levelStr))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid level of module %q: %w", module, err)
+		}
+
+		if module == "*" {
+			level = &parsed
+			continue
+		}
+		if module == "" {
+			return nil, nil, fmt.Errorf("invalid module level %q: empty module name", part)
+		}
+		modules[module] = parsed
+	}
+	return modules, level, nil
+}
+
+// levelFor returns the level of a named logger, the caller must hold l.mu
+func (l *Logger) levelFor(name string) slog.Level {
+	level := l.level
+	if name == "" {
+		return level
+	}
+
+	longest := -1
+	for module, moduleLevel := range l.modules {
+		if len(module) > longest && (name == module || strings.HasPrefix(name, module+".")) {
+			longest = len(module)
+			level = moduleLevel
+		}
+	}
+	return level
+}
+
+// copyModules returns a copy of the module levels
+func copyModules(modules map[string]slog.Level) map[string]slog.Level {
+	next := make(map[string]slog.Level, len(modules))
+	for module, level := range modules {
+		next[module] = level
+	}
+	return next
+}
@@ -0,0 +1,94 @@
+package gologger
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamedLoggers(t *testing.T) {
+	l := NewLogger()
+
+	var received []Record
+	l.RegisterRecordCallback(slog.LevelDebug, func(r Record) { received = append(received, r) })
+
+	if err := l.SetModuleLevels("db=debug, db.pool.stats=error, http=warn, *=info"); err != nil {
+		t.Fatal(err)
+	}
+	pool := l.Named("db").Named("pool")
+	stats := pool.Named("stats")
+	dbx := l.Named("dbx")
+	httpLogger := l.Named("http").With("component", "router")
+
+	pool.Debug("pool debug")
+	stats.Warn("stats warning")
+	dbx.Debug("dbx debug")
+	httpLogger.Info("http info")
+	httpLogger.Warn("http warning")
+	l.Debug("root debug")
+	l.Info("root info")
+
+	var messages []string
+	for _, r := range received {
+		messages = append(messages, r.Message)
+	}
+	if got := strings.Join(messages, ","); got != "pool debug,http warning,root info" {
+		t.Errorf("unexpected records %s", got)
+	}
+	if received[0].Logger != "db.pool" || formatFields(received[0].Fields) != " logger=db.pool" {
+		t.Errorf("expected logger field, got %q %v", received[0].Logger, received[0].Fields)
+	}
+
+	// levels can be changed at runtime
+	l.ResetModuleLevel("db")
+	l.SetModuleLevel("http", slog.LevelInfo)
+	pool.Debug("pool debug")
+	httpLogger.Info("http info")
+	if last := received[len(received)-1]; len(received) != 4 || last.Message != "http info" {
+		t.Errorf("expected only the http record after changing levels, got %d records", len(received))
+	}
+
+	if _, _, err := ParseModuleLevels("db=verbose"); err == nil || !strings.Contains(err.Error(), `"db"`) {
+		t.Errorf("expected error naming the module, got %v", err)
+	}
+	if err := l.SetModuleLevels("db"); err == nil {
+		t.Error("expected error for missing level")
+	}
+	if levels := l.ModuleLevels(); len(levels) != 2 {
+		t.Errorf("expected invalid spec to leave levels unchanged, got %v", levels)
+	}
+
+	t.Run("loki label", func(t *testing.T) {
+		received := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body, _ := io.ReadAll(gz)
+			received <- string(body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		l := NewLogger()
+		sink, err := l.UseLoki(LokiConfig{URL: server.URL, BatchWait: time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Named("db").Info("query")
+		if err := sink.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if body := <-received; !strings.Contains(body, `"logger":"db"`) {
+			t.Errorf("expected logger label, got %s", body)
+		}
+	})
+}
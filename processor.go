@@ -0,0 +1,88 @@
+package gologger
+
+import "fmt"
+
+// Processor transforms a record before it reaches the callbacks and sinks.
+// It returns false to drop the record. Records are shared between sinks, so a processor must not modify
+// Fields or Labels in place but replace them, e.g. with Record.Add.
+type Processor func(r Record) (Record, bool)
+
+// processorEntry is a processor registered on a logger, the id identifies it for removal
+type processorEntry struct {
+	id uint64
+	p  Processor
+}
+
+// AddProcessor adds a processor to the default logger, see Logger.AddProcessor
+func AddProcessor(p Processor) func() { return defaultLogger.AddProcessor(p) }
+
+// AddProcessor adds a processor that runs for every record that passes the level check, before any callback or sink.
+// Processors run in the order they were added. The returned function removes the processor again.
+func (l *Logger) AddProcessor(p Processor) func() {
+	l.mu.Lock()
+	id := l.addProcessor(p)
+	l.mu.Unlock()
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, entry := range l.processors {
+			if entry.id == id {
+				l.processors = append(l.processors[:i:i], l.processors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// WithProcessor adds a processor to the logger, see AddProcessor
+func WithProcessor(p Processor) Option {
+	return func(l *Logger) { l.addProcessor(p) }
+}
+
+// addProcessor appends a processor, the caller must hold l.mu
+func (l *Logger) addProcessor(p Processor) uint64 {
+	l.nextID++
+	l.processors = append(l.processors, processorEntry{id: l.nextID, p: p})
+	return l.nextID
+}
+
+// process runs the record through the processors in order and reports whether it should be dispatched.
+// A panicking processor is reported and skipped, so the record is not lost.
+func (l *Logger) process(source string, processors []Processor, r Record) (Record, bool) {
+	for i, p := range processors {
+		out, ok, panicked := l.runProcessor(fmt.Sprintf("%s %d", source, i+1), p, r)
+		if panicked {
+			continue
+		}
+		if !ok {
+			return Record{}, false
+		}
+		r = out
+	}
+	return r, true
+}
+
+// runProcessor calls a single processor and recovers a panic
+func (l *Logger) runProcessor(name string, p Processor, r Record) (out Record, ok bool, panicked bool) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			l.reportError(newPanicError(name, rec))
+			panicked = true
+		}
+	}()
+	out, ok = p(r)
+	return out, ok, false
+}
+
+// processedSink wraps a sink's Handle with the sink's own processors
+func (l *Logger) processedSink(name string, sink Sink, processors []Processor) RecordCallback {
+	if len(processors) == 0 {
+		return sink.Handle
+	}
+	return func(r Record) {
+		if r, ok := l.process("processor of sink "+name, processors, r); ok {
+			sink.Handle(r)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gologger
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestProcessors(t *testing.T) {
+	l := NewLogger(WithProcessor(func(r Record) (Record, bool) {
+		return r.Add("host", "test"), true
+	}))
+	l.AddProcessor(func(r Record) (Record, bool) {
+		return r, !strings.HasPrefix(r.Message, "noisy")
+	})
+
+	var all []Record
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { all = append(all, r) })
+
+	var events []string
+	sink := &testSink{name: "sink", events: &events}
+	l.RegisterSink("sink", slog.LevelInfo, sink, func(r Record) (Record, bool) {
+		return r.Add("sink", true), true
+	})
+
+	l.Info("hello", "key", "value")
+	l.Info("noisy health check")
+
+	if len(all) != 1 || len(sink.records) != 1 {
+		t.Fatalf("expected the noisy record to be dropped, got %d and %d records", len(all), len(sink.records))
+	}
+	if fields := fieldsToMap(all[0].Fields); fields["host"] != "test" || fields["sink"] != nil {
+		t.Errorf("expected only the global processor's fields, got %v", fields)
+	}
+	if fields := fieldsToMap(sink.records[0].Fields); fields["host"] != "test" || fields["sink"] != true {
+		t.Errorf("expected global and sink processor fields in order, got %v", fields)
+	}
+
+	t.Run("panicking processor is skipped", func(t *testing.T) {
+		var reported error
+		l := NewLogger(WithErrorHandler(func(err error) { reported = err }))
+		remove := l.AddProcessor(func(r Record) (Record, bool) { panic("boom") })
+
+		var received int
+		l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { received++ })
+		l.Info("test")
+
+		if received != 1 {
+			t.Errorf("expected the record to be dispatched, got %d records", received)
+		}
+		if reported == nil || !strings.Contains(reported.Error(), "processor 1 panicked") {
+			t.Errorf("expected reported panic, got %v", reported)
+		}
+
+		remove()
+		reported = nil
+		l.Info("test")
+		if reported != nil {
+			t.Errorf("expected removed processor not to run, got %v", reported)
+		}
+	})
+}
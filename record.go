@@ -0,0 +1,84 @@
+package gologger
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"runtime"
+	"time"
+)
+
+// Record is a single log event. It is created once per logging call and shared by all callbacks and sinks,
+// so every copy of the event carries the same timestamp.
+type Record struct {
+	Time    time.Time         // Time of the logging call
+	Level   slog.Level        // Level of the record
+	Message string            // Log message
+	Fields  []any             // Converted key-value pairs, including bound and context fields; groups are slog.GroupValue
+	PC      uintptr           // Program counter of the logging call, see Caller
+	Source  *slog.Source      // Call site of the logging call, only set with AddSource
+	Logger  string            // Name of the logger, see Named; it is also the first field
+	Labels  map[string]string // Labels of the logger, sinks merge them with their own labels
+	Context context.Context   // Context passed to the logging call, context.Background() otherwise
+}
+
+// RecordCallback is the function signature for subscribers that receive the complete record
+type RecordCallback func(r Record)
+
+// Caller returns the location of the logging call, or an empty frame if it is unknown
+func (r Record) Caller() runtime.Frame {
+	if r.PC == 0 {
+		return runtime.Frame{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	return frame
+}
+
+// Add returns a copy of the record with the key-value pairs appended to its fields as given, without stringer conversion
+func (r Record) Add(args ...any) Record {
+	fields := make([]any, 0, len(r.Fields)+len(args))
+	fields = append(fields, r.Fields...)
+	r.Fields = append(fields, args...)
+	return r
+}
+
+// RegisterRecordCallback registers a callback that receives every record at or above minLevel.
+// The returned function removes the callback again.
+func RegisterRecordCallback(minLevel slog.Level, cb RecordCallback) func() {
+	return defaultLogger.RegisterRecordCallback(minLevel, cb)
+}
+
+// RegisterRecordCallback registers a callback on the logger that receives every record at or above minLevel.
+// The returned function removes the callback again.
+func (l *Logger) RegisterRecordCallback(minLevel slog.Level, cb RecordCallback) func() {
+	return l.registerRange("", minLevel, slog.Level(math.MaxInt), cb)
+}
+
+// WithLabels sets labels that are attached to every record of the logger
+func WithLabels(labels map[string]string) Option {
+	return func(l *Logger) { l.labels = labels }
+}
+
+// callerPC returns the program counter of the function skip frames above the caller of callerPC
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	// skip runtime.Callers and callerPC itself
+	runtime.Callers(skip+2, pcs[:])
+	return pcs[0]
+}
+
+// mergeLabels returns the sink's labels combined with the record's labels, the sink's labels take precedence
+func mergeLabels(sinkLabels, recordLabels map[string]string) map[string]string {
+	if len(recordLabels) == 0 {
+		return sinkLabels
+	}
+
+	labels := make(map[string]string, len(sinkLabels)+len(recordLabels))
+	for k, v := range recordLabels {
+		labels[k] = v
+	}
+	for k, v := range sinkLabels {
+		labels[k] = v
+	}
+	return labels
+}
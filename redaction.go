@@ -0,0 +1,233 @@
+package gologger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces masked values unless hashing is enabled
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedKeys are the field keys masked when RedactionConfig.Keys is nil
+var DefaultRedactedKeys = []string{"password", "token", "authorization"}
+
+// Built-in patterns that are scanned for unless RedactionConfig.SkipDefaultPatterns is set.
+// Matches of CreditCardPattern are only masked if they pass the Luhn checksum.
+var (
+	EmailPattern      = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	CreditCardPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+	JWTPattern        = regexp.MustCompile(`\beyJ[a-zA-Z0-9_\-]+\.eyJ[a-zA-Z0-9_\-]+\.[a-zA-Z0-9_\-]+`)
+	BearerPattern     = regexp.MustCompile(`(?i)\bbearer\s+[a-zA-Z0-9._~+/\-]+=*`)
+)
+
+// RedactionConfig configures the masking of sensitive data
+type RedactionConfig struct {
+	Keys                []string         // Field keys whose values are masked at any group depth, case-insensitive; defaults to DefaultRedactedKeys
+	Patterns            []*regexp.Regexp // Additional patterns that are masked in messages and string values
+	SkipDefaultPatterns bool             // Don't scan for emails, credit card numbers, JWTs and bearer tokens
+	Hash                bool             // Replace values with a salted hash instead of Redacted, so equal values stay correlatable
+	Salt                string           // Salt of the hash
+}
+
+// redactor masks sensitive keys and patterns in records
+type redactor struct {
+	cfg      RedactionConfig
+	keys     map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// EnableRedaction masks sensitive data in every record of the default logger, see Logger.EnableRedaction
+func EnableRedaction(cfg RedactionConfig) { defaultLogger.EnableRedaction(cfg) }
+
+// EnableRedaction masks sensitive data in every record of the logger. It is applied after the logger's processors
+// and before any callback or sink sees the record, replacing an earlier configuration.
+func (l *Logger) EnableRedaction(cfg RedactionConfig) {
+	r := newRedactor(cfg)
+	l.mu.Lock()
+	l.redactor = r
+	l.mu.Unlock()
+}
+
+// DisableRedaction stops masking sensitive data in records of the default logger
+func DisableRedaction() { defaultLogger.DisableRedaction() }
+
+// DisableRedaction stops masking sensitive data in records of the logger
+func (l *Logger) DisableRedaction() {
+	l.mu.Lock()
+	l.redactor = nil
+	l.mu.Unlock()
+}
+
+// WithRedaction enables redaction on the logger, see EnableRedaction
+func WithRedaction(cfg RedactionConfig) Option {
+	return func(l *Logger) { l.redactor = newRedactor(cfg) }
+}
+
+func newRedactor(cfg RedactionConfig) *redactor {
+	keys := cfg.Keys
+	if keys == nil {
+		keys = DefaultRedactedKeys
+	}
+	r := &redactor{cfg: cfg, keys: make(map[string]bool, len(keys))}
+	for _, k := range keys {
+		r.keys[strings.ToLower(k)] = true
+	}
+	if !cfg.SkipDefaultPatterns {
+		r.patterns = append(r.patterns, EmailPattern, CreditCardPattern, JWTPattern, BearerPattern)
+	}
+	r.patterns = append(r.patterns, cfg.Patterns...)
+	return r
+}
+
+// redact returns a copy of the record with sensitive data masked
+func (r *redactor) redact(rec Record) Record {
+	rec.Message = r.scan(rec.Message)
+	fields := make([]any, len(rec.Fields))
+	for i := 0; i < len(rec.Fields); i += 2 {
+		fields[i] = rec.Fields[i]
+		if i+1 < len(rec.Fields) {
+			fields[i+1] = r.redactField(fmt.Sprint(rec.Fields[i]), rec.Fields[i+1])
+		}
+	}
+	rec.Fields = fields
+	return rec
+}
+
+// sensitive reports whether the key, or the last segment of a dotted key, is configured for masking
+func (r *redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	if r.keys[key] {
+		return true
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return r.keys[key[i+1:]]
+	}
+	return false
+}
+
+// redactField masks the value entirely if the key is sensitive and scans it for patterns otherwise
+func (r *redactor) redactField(key string, v any) any {
+	if _, ok := lazyOf(v); ok {
+		// masked once resolved, see resolveLazy
+		return v
+	}
+	if r.sensitive(key) {
+		return r.mask(fmt.Sprint(fieldValue(v)))
+	}
+	return r.redactValue(v)
+}
+
+// redactValue scans strings and descends into groups, slices and maps with string keys
+func (r *redactor) redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return r.scan(v)
+	case slog.Value:
+		v = v.Resolve()
+		switch v.Kind() {
+		case slog.KindString:
+			return slog.StringValue(r.scan(v.String()))
+		case slog.KindGroup:
+			attrs := v.Group()
+			redacted := make([]slog.Attr, len(attrs))
+			for i, a := range attrs {
+				redacted[i] = slog.Any(a.Key, r.redactField(a.Key, a.Value))
+			}
+			return slog.GroupValue(redacted...)
+		case slog.KindAny:
+			return r.redactValue(v.Any())
+		}
+		return v
+	case error:
+		// keep the error for errors.Is unless its message contains sensitive data
+		msg := v.Error()
+		if redacted := r.scan(msg); redacted != msg {
+			return redacted
+		}
+		return v
+	case fmt.Stringer:
+		// sinks print Stringers with %v, so their text is scanned like an error message
+		text := fmt.Sprint(v)
+		if redacted := r.scan(text); redacted != text {
+			return redacted
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		redacted := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			redacted[key] = r.redactField(key, iter.Value().Interface())
+		}
+		return redacted
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v
+		}
+		redacted := make([]any, rv.Len())
+		for i := range redacted {
+			redacted[i] = r.redactValue(rv.Index(i).Interface())
+		}
+		return redacted
+	}
+	return v
+}
+
+// scan masks every pattern match in s
+func (r *redactor) scan(s string) string {
+	for _, p := range r.patterns {
+		if p == CreditCardPattern {
+			// only mask valid card numbers, not IDs, timestamps or phone numbers of the same length
+			s = p.ReplaceAllStringFunc(s, func(m string) string {
+				if !luhnValid(m) {
+					return m
+				}
+				return r.mask(m)
+			})
+			continue
+		}
+		s = p.ReplaceAllStringFunc(s, r.mask)
+	}
+	return s
+}
+
+// luhnValid reports whether the digits of s, ignoring spaces and dashes, pass the Luhn checksum
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// mask returns the replacement for a sensitive value
+func (r *redactor) mask(s string) string {
+	if !r.cfg.Hash {
+		return Redacted
+	}
+	sum := sha256.Sum256([]byte(r.cfg.Salt + s))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
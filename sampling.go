@@ -0,0 +1,231 @@
+package gologger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+// SamplingConfig configures sampling and rate limiting of records, see EnableSampling
+type SamplingConfig struct {
+	Interval        time.Duration            // Window of the First and Thereafter counters, defaults to one second
+	First           int                      // Records with the same level and message passed per interval, 0 disables per-message sampling
+	Thereafter      int                      // After First, every Thereafter-th record passes, 0 drops the rest of the interval
+	Rates           map[slog.Level]RateLimit // Token-bucket limits per level, applied to the records that passed per-message sampling
+	SummaryInterval time.Duration            // Interval of the "suppressed N records" warning, 0 disables it; ignored for sinks
+}
+
+// RateLimit is a token bucket that refills PerSecond tokens per second and holds at most Burst tokens
+type RateLimit struct {
+	PerSecond float64
+	Burst     int // defaults to PerSecond rounded up
+}
+
+// sampler decides which records pass, it is shared by all goroutines logging to its logger or sink
+type sampler struct {
+	cfg         SamplingConfig
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[samplingKey]int
+	buckets     map[slog.Level]*bucket
+	suppressed  func()        // counts a suppressed record
+	stop        chan struct{} // closed to stop the summary, nil without one
+	stopped     chan struct{}
+	closeOnce   sync.Once
+}
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// EnableSampling samples the records of the default logger, see Logger.EnableSampling
+func EnableSampling(cfg SamplingConfig) { defaultLogger.EnableSampling(cfg) }
+
+// EnableSampling samples the records of the logger, replacing an earlier configuration.
+// Sampling runs after the level check and before the record is built, so suppressed records are cheap.
+// Suppressed records are counted, see Suppressed and SamplingConfig.SummaryInterval.
+func (l *Logger) EnableSampling(cfg SamplingConfig) {
+	s := l.newSampler(cfg)
+	l.mu.Lock()
+	previous := l.sampler
+	l.sampler = s
+	l.mu.Unlock()
+
+	previous.close()
+	s.startSummary(l)
+}
+
+// DisableSampling stops sampling the records of the default logger
+func DisableSampling() { defaultLogger.DisableSampling() }
+
+// DisableSampling stops sampling the records of the logger, sinks keep their own sampling
+func (l *Logger) DisableSampling() {
+	l.mu.Lock()
+	previous := l.sampler
+	l.sampler = nil
+	l.mu.Unlock()
+
+	previous.close()
+}
+
+// WithSampling enables sampling on the logger, see EnableSampling
+func WithSampling(cfg SamplingConfig) Option {
+	return func(l *Logger) {
+		l.sampler = l.newSampler(cfg)
+		l.sampler.startSummary(l)
+	}
+}
+
+// SetSinkSampling samples the records of the default logger's sink with the given name, see Logger.SetSinkSampling
+func SetSinkSampling(name string, cfg *SamplingConfig) error {
+	return defaultLogger.SetSinkSampling(name, cfg)
+}
+
+// SetSinkSampling gives every sink with the given name its own sampling, which replaces the logger's sampling for it.
+// A nil config makes the sinks follow the logger's sampling again.
+func (l *Logger) SetSinkSampling(name string, cfg *SamplingConfig) error {
+	var s *sampler
+	if cfg != nil {
+		s = l.newSampler(*cfg)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	found := false
+	for _, sink := range l.sinks {
+		if sink.name != name {
+			continue
+		}
+		found = true
+		for i := range l.callbacks {
+			if l.callbacks[i].id == sink.callbackID {
+				l.callbacks[i].sampler = s
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("no sink named %q", name)
+	}
+	return nil
+}
+
+// Suppressed returns the number of records the default logger's sampling suppressed
+func Suppressed() uint64 { return defaultLogger.Suppressed() }
+
+// Suppressed returns the number of records the logger's and its sinks' sampling suppressed.
+// A record suppressed for several sinks is counted once per sink.
+func (l *Logger) Suppressed() uint64 { return l.suppressed.Load() }
+
+func (l *Logger) newSampler(cfg SamplingConfig) *sampler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	s := &sampler{
+		cfg:        cfg,
+		counts:     make(map[samplingKey]int),
+		buckets:    make(map[slog.Level]*bucket, len(cfg.Rates)),
+		suppressed: func() { l.suppressed.Add(1) },
+	}
+	for level, limit := range cfg.Rates {
+		if limit.Burst <= 0 {
+			limit.Burst = int(math.Max(1, math.Ceil(limit.PerSecond)))
+		}
+		s.buckets[level] = &bucket{limit: limit, tokens: float64(limit.Burst)}
+	}
+	return s
+}
+
+// allow reports whether a record passes, counting it as suppressed otherwise
+func (s *sampler) allow(level slog.Level, msg string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.First > 0 {
+		// start a new window, forgetting the counters of the previous one
+		if now.Sub(s.windowStart) >= s.cfg.Interval {
+			s.windowStart = now
+			clear(s.counts)
+		}
+
+		key := samplingKey{level: level, msg: msg}
+		s.counts[key]++
+		n := s.counts[key]
+		if n > s.cfg.First && (s.cfg.Thereafter <= 0 || (n-s.cfg.First)%s.cfg.Thereafter != 0) {
+			s.suppressed()
+			return false
+		}
+	}
+
+	if b, ok := s.buckets[level]; ok && !b.take(now) {
+		s.suppressed()
+		return false
+	}
+	return true
+}
+
+// take refills the bucket and removes a token if one is available
+func (b *bucket) take(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.PerSecond)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// startSummary periodically logs the number of records suppressed since the last summary
+func (s *sampler) startSummary(l *Logger) {
+	if s.cfg.SummaryInterval <= 0 {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	reported := l.Suppressed()
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(s.cfg.SummaryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reported = l.reportSuppressed(reported)
+			case <-s.stop:
+				l.reportSuppressed(reported)
+				return
+			}
+		}
+	}()
+}
+
+// reportSuppressed logs the records suppressed since the last report, bypassing the sampling, and returns the new total
+func (l *Logger) reportSuppressed(reported uint64) uint64 {
+	total := l.Suppressed()
+	if total > reported {
+		l.emit(context.Background(), time.Time{}, slog.LevelWarn, 0, false, fmt.Sprintf("suppressed %d records", total-reported), "count", total-reported)
+	}
+	return total
+}
+
+// close stops the summary after reporting the remaining suppressed records
+func (s *sampler) close() {
+	if s == nil || s.stop == nil {
+		return
+	}
+	s.closeOnce.Do(func() { close(s.stop) })
+	<-s.stopped
+}
@@ -0,0 +1,81 @@
+package gologger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampling(t *testing.T) {
+	t.Run("first then every nth", func(t *testing.T) {
+		l := NewLogger(WithSampling(SamplingConfig{Interval: time.Hour, First: 3, Thereafter: 5}))
+
+		var received []string
+		l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { received = append(received, r.Message) })
+		for i := 0; i < 20; i++ {
+			l.Info("hot loop")
+		}
+		l.Info("other")
+
+		// records 1-3, 8, 13 and 18 of the hot loop pass
+		if len(received) != 7 {
+			t.Errorf("expected 7 records, got %d", len(received))
+		}
+		if l.Suppressed() != 14 {
+			t.Errorf("expected 14 suppressed records, got %d", l.Suppressed())
+		}
+	})
+
+	t.Run("token bucket per level", func(t *testing.T) {
+		l := NewLogger(WithLevel(slog.LevelDebug), WithSampling(SamplingConfig{
+			Rates: map[slog.Level]RateLimit{slog.LevelDebug: {PerSecond: 0.001, Burst: 2}},
+		}))
+
+		counts := make(map[slog.Level]int)
+		l.RegisterRecordCallback(slog.LevelDebug, func(r Record) { counts[r.Level]++ })
+		for i := 0; i < 5; i++ {
+			l.Debug("debug")
+			l.Info("info")
+		}
+
+		if counts[slog.LevelDebug] != 2 || counts[slog.LevelInfo] != 5 {
+			t.Errorf("expected 2 debug and 5 info records, got %v", counts)
+		}
+	})
+
+	t.Run("sink override and summary", func(t *testing.T) {
+		l := NewLogger(WithSampling(SamplingConfig{First: 1, SummaryInterval: time.Hour}))
+
+		var events []string
+		sampled := &testSink{name: "sampled", events: &events}
+		all := &testSink{name: "all", events: &events}
+		l.RegisterSink("sampled", slog.LevelInfo, sampled)
+		l.RegisterSink("all", slog.LevelInfo, all)
+		if err := l.SetSinkSampling("all", &SamplingConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.SetSinkSampling("missing", nil); err == nil {
+			t.Error("expected an error for an unknown sink")
+		}
+
+		for i := 0; i < 4; i++ {
+			l.Info("repeated")
+		}
+		if len(sampled.records) != 1 || len(all.records) != 4 {
+			t.Fatalf("expected 1 and 4 records, got %d and %d", len(sampled.records), len(all.records))
+		}
+
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		summary := sampled.records[len(sampled.records)-1]
+		if summary.Message != "suppressed 3 records" || summary.Level != slog.LevelWarn {
+			t.Errorf("expected summary on shutdown, got %s %q", summary.Level, summary.Message)
+		}
+		if !strings.Contains(formatFields(summary.Fields), "count=3") {
+			t.Errorf("expected count field, got %v", summary.Fields)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package gologger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+)
+
+// Sink is a log destination with its own lifecycle. File, database and Loki sinks implement it,
+// custom sinks can be added with RegisterSink.
+type Sink interface {
+	// Handle writes or buffers a single record
+	Handle(r Record)
+	// Flush writes out everything the sink buffered
+	Flush(ctx context.Context) error
+	// Close releases the sink's resources, records handled afterwards may be lost
+	Close(ctx context.Context) error
+}
+
+// SinkError is a failure of a single sink during Flush or Shutdown
+type SinkError struct {
+	Sink string // name of the sink
+	Op   string // "flush" or "close"
+	Err  error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("failed to %s sink %s: %v", e.Op, e.Sink, e.Err)
+}
+
+func (e *SinkError) Unwrap() error { return e.Err }
+
+// sinkEntry is a sink registered on a logger
+type sinkEntry struct {
+	id         uint64
+	callbackID uint64
+	name       string
+	minLevel   slog.Level
+	sink       Sink
+	remove     func()
+}
+
+// registration is embedded by the built-in sinks and removes them from their logger again
+type registration struct {
+	remove func()
+	once   sync.Once
+}
+
+// unregister removes the sink from its logger, it reports whether this was the first call
+func (r *registration) unregister() bool {
+	first := false
+	r.once.Do(func() {
+		first = true
+		if r.remove != nil {
+			r.remove()
+		}
+	})
+	return first
+}
+
+// RegisterSink registers a sink on the default logger, see Logger.RegisterSink
+func RegisterSink(name string, minLevel slog.Level, sink Sink, processors ...Processor) func() {
+	return defaultLogger.RegisterSink(name, minLevel, sink, processors...)
+}
+
+// RegisterSink registers a sink that handles every record at or above minLevel, including custom levels.
+// The name identifies the sink in internal errors. The returned function removes the sink again without closing it,
+// Shutdown flushes and closes all sinks that are still registered.
+// The processors only apply to this sink and run in order after the logger's processors.
+func (l *Logger) RegisterSink(name string, minLevel slog.Level, sink Sink, processors ...Processor) func() {
+	l.mu.Lock()
+	callbackID := l.addCallback(name, minLevel, slog.Level(math.MaxInt), l.processedSink(name, sink, processors))
+	l.nextID++
+	id := l.nextID
+	var once sync.Once
+	remove := func() {
+		once.Do(func() {
+			l.removeCallback(callbackID)
+			l.removeSink(id)
+		})
+	}
+	l.sinks = append(l.sinks, sinkEntry{id: id, callbackID: callbackID, name: name, minLevel: minLevel, sink: sink, remove: remove})
+	l.mu.Unlock()
+
+	return remove
+}
+
+// removeSink forgets the sink with the given id
+func (l *Logger) removeSink(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, entry := range l.sinks {
+		if entry.id == id {
+			l.sinks = append(l.sinks[:i:i], l.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// registeredSinks returns a snapshot of the registered sinks in registration order
+func (l *Logger) registeredSinks() []sinkEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	sinks := make([]sinkEntry, len(l.sinks))
+	copy(sinks, l.sinks)
+	return sinks
+}
+
+// Flush waits until the default logger processed all queued records and flushes every sink
+func Flush(ctx context.Context) error { return defaultLogger.Flush(ctx) }
+
+// Flush waits until all queued records are processed and then flushes every sink.
+// It returns ctx.Err() if the context ends before the queues are drained, sink failures are returned as SinkError.
+func (l *Logger) Flush(ctx context.Context) error {
+	if err := l.drainQueues(ctx); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, s := range l.registeredSinks() {
+		if err := s.sink.Flush(ctx); err != nil {
+			errs = append(errs, &SinkError{Sink: s.name, Op: "flush", Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown flushes and closes every sink of the default logger, see Logger.Shutdown
+func Shutdown(ctx context.Context) error { return defaultLogger.Shutdown(ctx) }
+
+// Shutdown drains the queues, then flushes and closes every registered sink in registration order.
+// Every sink is closed even if an earlier one fails, all failures are returned as SinkError. Shutdown returns once
+// ctx ends: sinks whose queue is still draining then are removed but not closed, and report ctx.Err().
+func (l *Logger) Shutdown(ctx context.Context) error {
+	// report the remaining repeated and suppressed records while the sinks are still open
+	l.mu.RLock()
+	dedup, sampler := l.dedup, l.sampler
+	l.mu.RUnlock()
+	dedup.close()
+	sampler.close()
+
+	var errs []error
+	if err := l.drainQueues(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, s := range l.registeredSinks() {
+		err := l.removeCallbackContext(ctx, s.callbackID)
+		s.remove()
+		if err != nil {
+			// the sink's queue is still draining, closing it now would race with its worker
+			errs = append(errs, &SinkError{Sink: s.name, Op: "close", Err: err})
+			continue
+		}
+		if err := s.sink.Flush(ctx); err != nil {
+			errs = append(errs, &SinkError{Sink: s.name, Op: "flush", Err: err})
+		}
+		if err := s.sink.Close(ctx); err != nil {
+			errs = append(errs, &SinkError{Sink: s.name, Op: "close", Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
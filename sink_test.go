@@ -0,0 +1,100 @@
+package gologger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type testSink struct {
+	name     string
+	events   *[]string
+	records  []Record
+	closeErr error
+}
+
+func (s *testSink) Handle(r Record) { s.records = append(s.records, r) }
+
+func (s *testSink) Flush(_ context.Context) error {
+	*s.events = append(*s.events, "flush "+s.name)
+	return nil
+}
+
+func (s *testSink) Close(_ context.Context) error {
+	*s.events = append(*s.events, "close "+s.name)
+	return s.closeErr
+}
+
+func TestShutdown(t *testing.T) {
+	l := NewLogger()
+
+	var events []string
+	first := &testSink{name: "first", events: &events}
+	second := &testSink{name: "second", events: &events, closeErr: errors.New("boom")}
+	l.RegisterSink("first", slog.LevelInfo, first)
+	l.RegisterSink("second", slog.LevelWarn, second)
+
+	l.Info("info")
+	l.Warn("warn")
+	if len(first.records) != 2 || len(second.records) != 1 {
+		t.Fatalf("expected 2 and 1 records, got %d and %d", len(first.records), len(second.records))
+	}
+
+	err := l.Shutdown(context.Background())
+	var sinkErr *SinkError
+	if !errors.As(err, &sinkErr) || sinkErr.Sink != "second" || sinkErr.Op != "close" {
+		t.Errorf("expected close error of sink second, got %v", err)
+	}
+
+	expected := []string{"flush first", "close first", "flush second", "close second"}
+	if len(events) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("expected events %v, got %v", expected, events)
+			break
+		}
+	}
+
+	// closed sinks no longer receive records
+	l.Warn("after shutdown")
+	if len(first.records) != 2 || len(second.records) != 1 {
+		t.Error("expected no records after shutdown")
+	}
+	if err := l.Shutdown(context.Background()); err != nil {
+		t.Errorf("expected second shutdown to be a no-op, got %v", err)
+	}
+}
+
+// slowSink takes a while to handle every record
+type slowSink struct{ delay time.Duration }
+
+func (s *slowSink) Handle(Record)                 { time.Sleep(s.delay) }
+func (s *slowSink) Flush(_ context.Context) error { return nil }
+func (s *slowSink) Close(_ context.Context) error { return nil }
+
+func TestShutdownDeadline(t *testing.T) {
+	l := NewLogger(WithAsync(AsyncConfig{QueueSize: 100}))
+	l.RegisterSink("slow", slog.LevelInfo, &slowSink{delay: 100 * time.Millisecond})
+	for i := 0; i < 30; i++ {
+		l.Info("queued")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected shutdown to return at its deadline, took %v", elapsed)
+	}
+	var sinkErr *SinkError
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &sinkErr) || sinkErr.Sink != "slow" {
+		t.Errorf("expected deadline error of sink slow, got %v", err)
+	}
+	if sinks := l.registeredSinks(); len(sinks) != 0 {
+		t.Errorf("expected sink to be removed, got %d sinks", len(sinks))
+	}
+}
@@ -0,0 +1,94 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// packageDir is the directory of gologger's sources, whose frames are skipped when looking for the caller
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// helpers holds the functions marked with Helper
+var helpers sync.Map
+
+// SetAddSource enables or disables capturing the call site on the default logger, see Logger.SetAddSource
+func SetAddSource(enabled bool) { defaultLogger.SetAddSource(enabled) }
+
+// SetAddSource enables or disables capturing the call site of every logging call in Record.Source.
+// Sinks render it as caller, the database sink only with DbConfig.SourceColumn.
+func (l *Logger) SetAddSource(enabled bool) {
+	l.mu.Lock()
+	l.addSource = enabled
+	l.mu.Unlock()
+}
+
+// WithAddSource enables capturing the call site on the logger, see SetAddSource
+func WithAddSource() Option {
+	return func(l *Logger) { l.addSource = true }
+}
+
+// Helper marks the calling function as a logging helper, like testing.T.Helper.
+// Helpers are skipped when capturing the call site, so records point to the helper's caller instead.
+func Helper() {
+	frame := frameOf(callerPC(1))
+	helpers.Store(frame.Function, true)
+}
+
+// frameOf returns the frame of a program counter
+func frameOf(pc uintptr) runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame
+}
+
+// isHelper reports whether the frame belongs to a function marked with Helper
+func isHelper(frame runtime.Frame) bool {
+	_, ok := helpers.Load(frame.Function)
+	return ok
+}
+
+// isInternal reports whether the frame belongs to gologger itself or log/slog
+func isInternal(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "log/slog.") {
+		return true
+	}
+	return filepath.Dir(frame.File) == packageDir && !strings.HasSuffix(frame.File, "_test.go")
+}
+
+// sourcePC returns pc unless it points into a helper, then it walks the stack of the current goroutine
+// past gologger, log/slog and all helpers
+func sourcePC(pc uintptr) uintptr {
+	if pc == 0 || !isHelper(frameOf(pc)) {
+		return pc
+	}
+
+	var pcs [64]uintptr
+	// skip runtime.Callers and sourcePC
+	n := runtime.Callers(2, pcs[:])
+	for _, candidate := range pcs[:n] {
+		if frame := frameOf(candidate); !isInternal(frame) && !isHelper(frame) {
+			return candidate
+		}
+	}
+	return pc
+}
+
+// source returns the slog.Source of a program counter, or nil if it is unknown
+func source(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+	frame := frameOf(pc)
+	return &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+}
+
+// formatSource renders a source as file:line
+func formatSource(s *slog.Source) string {
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
@@ -0,0 +1,60 @@
+package gologger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func logThroughHelper(l *Logger, msg string) {
+	Helper()
+	l.Info(msg)
+}
+
+func TestAddSource(t *testing.T) {
+	l := NewLogger()
+
+	var sources []*slog.Source
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { sources = append(sources, r.Source) })
+
+	l.Info("disabled")
+	if sources[0] != nil {
+		t.Errorf("expected no source without AddSource, got %v", sources[0])
+	}
+
+	l.SetAddSource(true)
+	l.Info("direct")
+	logThroughHelper(l, "helper")
+	slog.New(l.Handler()).Info("slog")
+
+	for i, src := range sources[1:] {
+		if src == nil || !strings.HasSuffix(src.Function, "TestAddSource") || filepath.Base(src.File) != "source_test.go" {
+			t.Errorf("record %d: expected the test function as source, got %+v", i+1, src)
+		}
+	}
+
+	t.Run("file sink renders caller", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "test.log")
+		l := NewLogger(WithAddSource())
+		if _, err := l.UseFile(FileConfig{Path: path, FormatJson: true}); err != nil {
+			t.Fatal(err)
+		}
+		l.Info("test")
+		l.StopFile()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entry jsonLogEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(entry.Caller, "source_test.go:") {
+			t.Errorf("expected caller in JSON entry, got %q", entry.Caller)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package gologger
+
+import "reflect"
+
+// stringerRegistry holds the string converters and key formatters of a logger.
+// It is replaced on registration, so it can be read without holding the logger's lock.
+type stringerRegistry struct {
+	exact      map[reflect.Type]StringConverter // converters of concrete types
+	interfaces []interfaceStringer              // converters of interface types in registration order
+	formatters []keyFormatter                   // key formatters in registration order
+}
+
+// interfaceStringer is a converter for all types implementing an interface
+type interfaceStringer struct {
+	iface     reflect.Type
+	converter StringConverter
+}
+
+func newStringerRegistry() *stringerRegistry {
+	return &stringerRegistry{exact: make(map[reflect.Type]StringConverter)}
+}
+
+// with returns a copy of the registry with the converter registered for t.
+// Re-registering an interface replaces its converter but keeps its position.
+func (r *stringerRegistry) with(t reflect.Type, converter StringConverter) *stringerRegistry {
+	next := &stringerRegistry{
+		exact:      make(map[reflect.Type]StringConverter, len(r.exact)+1),
+		interfaces: make([]interfaceStringer, 0, len(r.interfaces)+1),
+		formatters: r.formatters,
+	}
+	for k, v := range r.exact {
+		next.exact[k] = v
+	}
+	next.interfaces = append(next.interfaces, r.interfaces...)
+
+	if t.Kind() != reflect.Interface {
+		next.exact[t] = converter
+		return next
+	}
+	for i, entry := range next.interfaces {
+		if entry.iface == t {
+			next.interfaces[i].converter = converter
+			return next
+		}
+	}
+	next.interfaces = append(next.interfaces, interfaceStringer{iface: t, converter: converter})
+	return next
+}
+
+// empty reports whether no converter or key formatter is registered
+func (r *stringerRegistry) empty() bool {
+	return len(r.exact) == 0 && len(r.interfaces) == 0 && len(r.formatters) == 0
+}
+
+// lookup returns the converter for t or the values t points to and the type it was registered for.
+// Concrete types take precedence over interfaces, which are checked in registration order.
+func (r *stringerRegistry) lookup(t reflect.Type) (StringConverter, reflect.Type, bool) {
+	if converter, elem, ok := r.lookupExact(t); ok {
+		return converter, elem, true
+	}
+	return r.lookupInterface(t)
+}
+
+// lookupExact returns the converter registered for t or the type t points to
+func (r *stringerRegistry) lookupExact(t reflect.Type) (StringConverter, reflect.Type, bool) {
+	for elem := t; ; elem = elem.Elem() {
+		if converter, ok := r.exact[elem]; ok {
+			return converter, elem, true
+		}
+		if elem.Kind() != reflect.Ptr {
+			return nil, nil, false
+		}
+	}
+}
+
+// lookupInterface returns the first converter registered for an interface implemented by t or the type t points to
+func (r *stringerRegistry) lookupInterface(t reflect.Type) (StringConverter, reflect.Type, bool) {
+	for elem := t; ; elem = elem.Elem() {
+		for _, entry := range r.interfaces {
+			if elem.Implements(entry.iface) {
+				return entry.converter, elem, true
+			}
+		}
+		if elem.Kind() != reflect.Ptr {
+			return nil, nil, false
+		}
+	}
+}
+
+// convertsElem reports whether elements of type t may be converted, either through pointers or nested collections.
+// Elements of interface type are checked one by one.
+func (r *stringerRegistry) convertsElem(t reflect.Type) bool {
+	for {
+		if t.Kind() == reflect.Interface {
+			return true
+		}
+		if _, _, ok := r.lookup(t); ok {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
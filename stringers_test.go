@@ -0,0 +1,59 @@
+package gologger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+type temperature float64
+
+func (t temperature) String() string { return fmt.Sprintf("%.1f°C", float64(t)) }
+
+func TestStringerMatching(t *testing.T) {
+	l := NewLogger()
+	RegisterStringerFor(l, func(err error) string { return "error: " + err.Error() })
+	RegisterStringerFor(l, func(s fmt.Stringer) string { return "stringer: " + s.String() })
+	RegisterStringerFor(l, func(t time.Time) string { return t.Format(time.DateOnly) })
+
+	var fields map[string]any
+	l.RegisterRecordCallback(slog.LevelInfo, func(r Record) { fields = fieldsToMap(r.Fields) })
+
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var nilTime *time.Time
+	temp := temperature(21.5)
+	l.Info("test",
+		"err", errors.New("boom"),
+		"temp", temp,
+		"date", date,
+		"date_ptr", &date,
+		"nil_ptr", nilTime,
+		"temps", []temperature{temp, 22},
+		"temp_ptrs", map[string]*temperature{"kitchen": &temp},
+		"mixed", []any{temp, 1},
+		"plain", []int{1, 2},
+	)
+
+	expected := map[string]any{
+		"err":       "error: boom",
+		"temp":      "stringer: 21.5°C",
+		"date":      "2024-05-01", // concrete types take precedence over fmt.Stringer
+		"date_ptr":  "2024-05-01",
+		"nil_ptr":   nil,
+		"temps":     "[stringer: 21.5°C stringer: 22.0°C]",
+		"temp_ptrs": "map[kitchen:stringer: 21.5°C]",
+		"mixed":     "[stringer: 21.5°C 1]",
+		"plain":     "[1 2]",
+	}
+	for key, want := range expected {
+		got := fields[key]
+		if want != nil {
+			got = fmt.Sprint(got)
+		}
+		if got != want {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+	}
+}
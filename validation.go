@@ -0,0 +1,128 @@
+package gologger
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// ValidationMode controls how invalid key-value arguments are handled
+type ValidationMode int
+
+const (
+	// ValidationPanic panics on invalid arguments, this is the default and meant for development and tests
+	ValidationPanic ValidationMode = iota
+	// ValidationRepair logs the record with the invalid arguments stored under BadKey and reports the problem to the ErrorHandler
+	ValidationRepair
+	// ValidationSilent repairs the arguments like ValidationRepair, but does not report anything
+	ValidationSilent
+)
+
+// BadKey is the key used for arguments that could not be paired with a valid key, like slog's !BADKEY
+const BadKey = "!BADKEY"
+
+// SetValidationMode sets how the default logger handles invalid arguments
+func SetValidationMode(mode ValidationMode) { defaultLogger.SetValidationMode(mode) }
+
+// SetValidationMode sets how the logger handles invalid arguments
+func (l *Logger) SetValidationMode(mode ValidationMode) {
+	l.mu.Lock()
+	l.validation = mode
+	l.mu.Unlock()
+}
+
+// WithValidationMode sets how the logger handles invalid arguments
+func WithValidationMode(mode ValidationMode) Option {
+	return func(l *Logger) { l.validation = mode }
+}
+
+// validateArgs checks that args are valid key-value pairs and handles violations according to the validation mode.
+// It returns the args to log, which are repaired unless the mode is ValidationPanic.
+// slog.Attr arguments in key position are expanded into key-value pairs first.
+func (l *Logger) validateArgs(args []any) []any {
+	args = expandAttrs(args)
+	err := checkArgs(args)
+	if err == nil {
+		return args
+	}
+
+	l.mu.RLock()
+	mode := l.validation
+	l.mu.RUnlock()
+
+	switch mode {
+	case ValidationRepair:
+		l.reportError(err)
+		return repairArgs(args)
+	case ValidationSilent:
+		return repairArgs(args)
+	default:
+		panic(err.Error())
+	}
+}
+
+// expandAttrs replaces slog.Attr arguments in key position, including slog.Group, by their key and value
+func expandAttrs(args []any) []any {
+	for i := 0; i < len(args); i += 2 {
+		if _, ok := args[i].(slog.Attr); !ok {
+			continue
+		}
+
+		expanded := make([]any, i, len(args)+1)
+		copy(expanded, args[:i])
+		for i < len(args) {
+			if a, ok := args[i].(slog.Attr); ok {
+				expanded = appendAttr(expanded, a)
+				i++
+				continue
+			}
+			expanded = append(expanded, args[i:min(i+2, len(args))]...)
+			i += 2
+		}
+		return expanded
+	}
+	return args
+}
+
+// checkArgs returns an error describing the first violation if args are not valid key-value pairs
+func checkArgs(args []any) error {
+	// Validate args are in key-value pairs
+	if len(args)%2 != 0 {
+		return fmt.Errorf("invalid number of arguments to log call: got %d, expected even number of key-value pairs", len(args))
+	}
+
+	// Validate keys are strings
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			return fmt.Errorf("invalid key type at position %d: got %T, expected string", i, args[i])
+		}
+		if key == "" {
+			return fmt.Errorf("empty string key at position %d", i)
+		}
+	}
+	return nil
+}
+
+// repairArgs turns args into valid key-value pairs the way slog does:
+// a non-string or empty key and a dangling key are stored as value under BadKey
+func repairArgs(args []any) []any {
+	repaired := make([]any, 0, len(args)+2)
+	for i := 0; i < len(args); {
+		key, ok := args[i].(string)
+		switch {
+		case !ok:
+			repaired = append(repaired, BadKey, args[i])
+			i++
+		case i+1 >= len(args):
+			repaired = append(repaired, BadKey, key)
+			i++
+		case key == "":
+			repaired = append(repaired, BadKey, args[i+1])
+			i += 2
+		default:
+			repaired = append(repaired, key, args[i+1])
+			i += 2
+		}
+	}
+	return repaired
+}